@@ -3,58 +3,37 @@ package main
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sort"
 	"testing"
 
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/fixtures/fixturestest"
 	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
 )
 
 func TestCreateAuthorBook(t *testing.T) {
-	authorUuid := uuid.New()
-	publisherUuid := uuid.New()
-	bookUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
 
 	tests := []struct {
 		scenario string
-		input    struct {
-			createAuthorParams     sqlc.CreateAuthorParams
-			createPublisherParams  sqlc.CreatePublisherParams
-			createBookParams       sqlc.CreateBookParams
-			createAuthorBookParams sqlc.CreateAuthorBookParams
-		}
-		expected sqlc.AuthorBook
+		input    func(f *fixturestest.Factory) sqlc.CreateAuthorBookParams
 	}{
 		{
 			scenario: "create author_book",
-			input: struct {
-				createAuthorParams     sqlc.CreateAuthorParams
-				createPublisherParams  sqlc.CreatePublisherParams
-				createBookParams       sqlc.CreateBookParams
-				createAuthorBookParams sqlc.CreateAuthorBookParams
-			}{
-				createAuthorParams: sqlc.CreateAuthorParams{
-					Uuid: authorUuid,
-					Name: "author001",
-					Bio:  sql.NullString{String: "author001", Valid: true},
-				},
-				createPublisherParams: sqlc.CreatePublisherParams{
-					Uuid: publisherUuid,
-					Name: "publisher001",
-				},
-				createBookParams: sqlc.CreateBookParams{
-					Uuid:          bookUuid,
-					Title:         "book001",
-					PublisherUuid: publisherUuid,
-				},
-				createAuthorBookParams: sqlc.CreateAuthorBookParams{
-					AuthorUuid: authorUuid,
-					BookUuid:   bookUuid,
-				},
+			input: func(f *fixturestest.Factory) sqlc.CreateAuthorBookParams {
+				return f.AuthorBook()
 			},
-			expected: sqlc.AuthorBook{
-				AuthorUuid: authorUuid,
-				BookUuid:   bookUuid,
+		},
+		{
+			scenario: "create second author of a book",
+			input: func(f *fixturestest.Factory) sqlc.CreateAuthorBookParams {
+				link := f.AuthorBook()
+				return f.AuthorBook(func(p *sqlc.CreateAuthorBookParams) {
+					p.BookUuid = link.BookUuid
+				})
 			},
 		},
 	}
@@ -78,97 +57,47 @@ func TestCreateAuthorBook(t *testing.T) {
 
 			queries = queries.WithTx(tx)
 
-			// crete author
-			ctx := context.Background()
-			err = queries.CreateAuthor(ctx, tt.input.createAuthorParams)
-			if err != nil {
-				t.Error(err)
-			}
-
-			// create publisher
-			err = queries.CreatePublisher(ctx, tt.input.createPublisherParams)
-			if err != nil {
-				t.Error(err)
-			}
-
-			// crete book
-			err = queries.CreateBook(ctx, tt.input.createBookParams)
-			if err != nil {
-				t.Error(err)
-			}
-
-			// create author_book
-			err = queries.CreateAuthorBook(ctx, tt.input.createAuthorBookParams)
-			if err != nil {
-				t.Error(err)
-			}
+			// create author, publisher, book and author_book
+			link := tt.input(fixturestest.New(t, queries))
 
 			// get author_book
+			ctx := context.Background()
 			authorBook, err := queries.GetAuthorBook(
 				ctx,
 				sqlc.GetAuthorBookParams{
-					AuthorUuid: tt.input.createAuthorBookParams.AuthorUuid,
-					BookUuid:   tt.input.createAuthorBookParams.BookUuid,
+					AuthorUuid: link.AuthorUuid,
+					BookUuid:   link.BookUuid,
 				},
 			)
 			if err != nil {
 				t.Error(err)
 			}
 
-			if authorBook != tt.expected {
-				t.Errorf("got=%v, want=%v", authorBook, tt.expected)
+			checkTimestamps(t, &authorBook)
+			expected := sqlc.AuthorBook{
+				AuthorUuid: link.AuthorUuid,
+				BookUuid:   link.BookUuid,
+			}
+			if authorBook != expected {
+				t.Errorf("got=%v, want=%v", authorBook, expected)
 			}
 		})
 	}
 }
 
 func TestDeleteAuthorBook(t *testing.T) {
-	authorUuid := uuid.New()
-	publisherUuid := uuid.New()
-	bookUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
 
 	tests := []struct {
 		scenario string
-		input    struct {
-			createAuthorParams     sqlc.CreateAuthorParams
-			createPublisherParams  sqlc.CreatePublisherParams
-			createBookParams       sqlc.CreateBookParams
-			createAuthorBookParams sqlc.CreateAuthorBookParams
-			deleteAuthorBookParams sqlc.DeleteAuthorBookParams
-		}
+		input    func(f *fixturestest.Factory) sqlc.CreateAuthorBookParams
 		expected error
 	}{
 		{
 			scenario: "delete author_book",
-			input: struct {
-				createAuthorParams     sqlc.CreateAuthorParams
-				createPublisherParams  sqlc.CreatePublisherParams
-				createBookParams       sqlc.CreateBookParams
-				createAuthorBookParams sqlc.CreateAuthorBookParams
-				deleteAuthorBookParams sqlc.DeleteAuthorBookParams
-			}{
-				createAuthorParams: sqlc.CreateAuthorParams{
-					Uuid: authorUuid,
-					Name: "author001",
-					Bio:  sql.NullString{String: "author001", Valid: true},
-				},
-				createPublisherParams: sqlc.CreatePublisherParams{
-					Uuid: publisherUuid,
-					Name: "publisher001",
-				},
-				createBookParams: sqlc.CreateBookParams{
-					Uuid:          bookUuid,
-					Title:         "book001",
-					PublisherUuid: publisherUuid,
-				},
-				createAuthorBookParams: sqlc.CreateAuthorBookParams{
-					AuthorUuid: authorUuid,
-					BookUuid:   bookUuid,
-				},
-				deleteAuthorBookParams: sqlc.DeleteAuthorBookParams{
-					AuthorUuid: authorUuid,
-					BookUuid:   bookUuid,
-				},
+			input: func(f *fixturestest.Factory) sqlc.CreateAuthorBookParams {
+				return f.AuthorBook()
 			},
 			expected: sql.ErrNoRows,
 		},
@@ -193,45 +122,18 @@ func TestDeleteAuthorBook(t *testing.T) {
 
 			queries = queries.WithTx(tx)
 
-			// crete author
-			ctx := context.Background()
-			err = queries.CreateAuthor(ctx, tt.input.createAuthorParams)
-			if err != nil {
-				t.Error(err)
-			}
-
-			// create publisher
-			err = queries.CreatePublisher(ctx, tt.input.createPublisherParams)
-			if err != nil {
-				t.Error(err)
-			}
-
-			// crete book
-			err = queries.CreateBook(ctx, tt.input.createBookParams)
-			if err != nil {
-				t.Error(err)
-			}
-
-			// create author_book
-			err = queries.CreateAuthorBook(ctx, tt.input.createAuthorBookParams)
-			if err != nil {
-				t.Error(err)
-			}
+			// create author, publisher, book and author_book
+			link := tt.input(fixturestest.New(t, queries))
 
 			// delete author_book
-			err = queries.DeleteAuthorBook(ctx, tt.input.deleteAuthorBookParams)
+			ctx := context.Background()
+			_, err = queries.DeleteAuthorBook(ctx, sqlc.DeleteAuthorBookParams(link))
 			if err != nil {
 				t.Error(err)
 			}
 
 			// get author_book
-			_, err = queries.GetAuthorBook(
-				ctx,
-				sqlc.GetAuthorBookParams{
-					AuthorUuid: tt.input.deleteAuthorBookParams.AuthorUuid,
-					BookUuid:   tt.input.deleteAuthorBookParams.BookUuid,
-				},
-			)
+			_, err = queries.GetAuthorBook(ctx, sqlc.GetAuthorBookParams(link))
 			if err != tt.expected {
 				t.Errorf("got=%v, want=%v", err, tt.expected)
 			}
@@ -240,14 +142,17 @@ func TestDeleteAuthorBook(t *testing.T) {
 }
 
 func TestListAuthorBooks(t *testing.T) {
-	authorUuids := []uuid.UUID{
-		uuid.New(),
-		uuid.New(),
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
 	}
-	publisherUuid := uuid.New()
-	bookUuids := []uuid.UUID{
-		uuid.New(),
-		uuid.New(),
+	publisherUuid := binuuid.New()
+	bookUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
 	}
 
 	tests := []struct {
@@ -426,3 +331,192 @@ func TestListAuthorBooks(t *testing.T) {
 		})
 	}
 }
+
+func TestListBooksByAuthor(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+	bookUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+
+	tests := []struct {
+		scenario string
+		input    binuuid.UUID
+		expected []binuuid.UUID
+	}{
+		{
+			scenario: "author with two books",
+			input:    authorUuids[0],
+			expected: []binuuid.UUID{bookUuids[0], bookUuids[1]},
+		},
+		{
+			scenario: "author with one book",
+			input:    authorUuids[1],
+			expected: []binuuid.UUID{bookUuids[0]},
+		},
+		{
+			scenario: "missing author",
+			input:    binuuid.New(),
+			expected: []binuuid.UUID{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			queries := sqlc.New(db)
+
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			queries = queries.WithTx(tx)
+
+			// create author_books
+			ctx := context.Background()
+			createAuthorBooks(ctx, t, queries, authorUuids, bookUuids)
+
+			// list books by author
+			books, err := queries.ListBooksByAuthor(ctx, sqlc.ListBooksByAuthorParams{
+				AuthorUuid: tt.input,
+				Limit:      10,
+			})
+			if err != nil {
+				t.Error(err)
+			}
+
+			sort.Slice(tt.expected, func(i, j int) bool {
+				return tt.expected[i].String() < tt.expected[j].String()
+			})
+
+			if len(books) != len(tt.expected) {
+				t.Fatalf("got=%v, want=%v", books, tt.expected)
+			}
+			for i := range books {
+				if books[i].Uuid != tt.expected[i] {
+					t.Errorf("got=%v, want=%v", books[i].Uuid, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestListAuthorsByBook(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+	bookUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+
+	tests := []struct {
+		scenario string
+		input    binuuid.UUID
+		expected []binuuid.UUID
+	}{
+		{
+			scenario: "book with two authors",
+			input:    bookUuids[0],
+			expected: []binuuid.UUID{authorUuids[0], authorUuids[1]},
+		},
+		{
+			scenario: "book with one author",
+			input:    bookUuids[1],
+			expected: []binuuid.UUID{authorUuids[0]},
+		},
+		{
+			scenario: "missing book",
+			input:    binuuid.New(),
+			expected: []binuuid.UUID{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			queries := sqlc.New(db)
+
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			queries = queries.WithTx(tx)
+
+			// create author_books
+			ctx := context.Background()
+			createAuthorBooks(ctx, t, queries, authorUuids, bookUuids)
+
+			// list authors by book
+			authors, err := queries.ListAuthorsByBook(ctx, sqlc.ListAuthorsByBookParams{
+				BookUuid: tt.input,
+				Limit:    10,
+			})
+			if err != nil {
+				t.Error(err)
+			}
+
+			sort.Slice(tt.expected, func(i, j int) bool {
+				return tt.expected[i].String() < tt.expected[j].String()
+			})
+
+			if len(authors) != len(tt.expected) {
+				t.Fatalf("got=%v, want=%v", authors, tt.expected)
+			}
+			for i := range authors {
+				if authors[i].Uuid != tt.expected[i] {
+					t.Errorf("got=%v, want=%v", authors[i].Uuid, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+// createAuthorBooks creates two authors and two books with one publisher
+// and links the first author to both books and the second author to the
+// first book.
+func createAuthorBooks(ctx context.Context, t *testing.T, queries *sqlc.Queries, authorUuids, bookUuids []binuuid.UUID) {
+	t.Helper()
+
+	publisherUuid := binuuid.New()
+	err := queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, authorUuid := range authorUuids {
+		err := queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: authorUuid, Name: fmt.Sprintf("author%03d", i+1)})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, bookUuid := range bookUuids {
+		err := queries.CreateBook(ctx, sqlc.CreateBookParams{Uuid: bookUuid, Title: fmt.Sprintf("book%03d", i+1), PublisherUuid: publisherUuid})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, link := range []sqlc.CreateAuthorBookParams{
+		{AuthorUuid: authorUuids[0], BookUuid: bookUuids[0]},
+		{AuthorUuid: authorUuids[0], BookUuid: bookUuids[1]},
+		{AuthorUuid: authorUuids[1], BookUuid: bookUuids[0]},
+	} {
+		err := queries.CreateAuthorBook(ctx, link)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
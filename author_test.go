@@ -6,14 +6,18 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/google/uuid"
 )
 
 func TestCreateAuthor(t *testing.T) {
-	authorUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
@@ -34,9 +38,10 @@ func TestCreateAuthor(t *testing.T) {
 				},
 			},
 			expected: sqlc.Author{
-				Uuid: authorUuid,
-				Name: "author001",
-				Bio:  sql.NullString{String: "author001", Valid: true},
+				Uuid:    authorUuid,
+				Name:    "author001",
+				Bio:     sql.NullString{String: "author001", Valid: true},
+				Version: 1,
 			},
 		},
 	}
@@ -73,6 +78,7 @@ func TestCreateAuthor(t *testing.T) {
 				t.Error(err)
 			}
 
+			checkTimestamps(t, &author)
 			if author != tt.expected {
 				t.Errorf("got=%v, want=%v", author, tt.expected)
 			}
@@ -81,7 +87,10 @@ func TestCreateAuthor(t *testing.T) {
 }
 
 func TestUpdateAuthor(t *testing.T) {
-	authorUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
@@ -103,15 +112,17 @@ func TestUpdateAuthor(t *testing.T) {
 					Bio:  sql.NullString{String: "author001", Valid: true},
 				},
 				updateAuthorParams: sqlc.UpdateAuthorParams{
-					Name: "Updated: author001",
-					Bio:  sql.NullString{String: "Updated: author001", Valid: true},
-					Uuid: authorUuid,
+					Name:    "Updated: author001",
+					Bio:     sql.NullString{String: "Updated: author001", Valid: true},
+					Uuid:    authorUuid,
+					Version: 1,
 				},
 			},
 			expected: sqlc.Author{
-				Uuid: authorUuid,
-				Name: "Updated: author001",
-				Bio:  sql.NullString{String: "Updated: author001", Valid: true},
+				Uuid:    authorUuid,
+				Name:    "Updated: author001",
+				Bio:     sql.NullString{String: "Updated: author001", Valid: true},
+				Version: 2,
 			},
 		},
 	}
@@ -143,7 +154,7 @@ func TestUpdateAuthor(t *testing.T) {
 			}
 
 			// update author
-			err = queries.UpdateAuthor(ctx, tt.input.updateAuthorParams)
+			_, err = queries.UpdateAuthor(ctx, tt.input.updateAuthorParams)
 			if err != nil {
 				t.Error(err)
 			}
@@ -154,6 +165,7 @@ func TestUpdateAuthor(t *testing.T) {
 				t.Error(err)
 			}
 
+			checkTimestamps(t, &author)
 			if author != tt.expected {
 				t.Errorf("got=%v, want=%v", author, tt.expected)
 			}
@@ -162,13 +174,16 @@ func TestUpdateAuthor(t *testing.T) {
 }
 
 func TestDeleteAuthor(t *testing.T) {
-	authorUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
 		input    struct {
 			createAuthorParams sqlc.CreateAuthorParams
-			deleteAuthorUuid   uuid.UUID
+			deleteAuthorUuid   binuuid.UUID
 		}
 		expected error
 	}{
@@ -176,7 +191,7 @@ func TestDeleteAuthor(t *testing.T) {
 			scenario: "delete author",
 			input: struct {
 				createAuthorParams sqlc.CreateAuthorParams
-				deleteAuthorUuid   uuid.UUID
+				deleteAuthorUuid   binuuid.UUID
 			}{
 				createAuthorParams: sqlc.CreateAuthorParams{
 					Uuid: authorUuid,
@@ -216,7 +231,7 @@ func TestDeleteAuthor(t *testing.T) {
 			}
 
 			// delete author
-			err = queries.DeleteAuthor(ctx, tt.input.deleteAuthorUuid)
+			_, err = queries.DeleteAuthor(ctx, tt.input.deleteAuthorUuid)
 			if err != nil {
 				t.Error(err)
 			}
@@ -231,9 +246,12 @@ func TestDeleteAuthor(t *testing.T) {
 }
 
 func TestListAuthors(t *testing.T) {
-	authorUuids := []uuid.UUID{
-		uuid.New(),
-		uuid.New(),
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
 	}
 
 	tests := []struct {
@@ -261,14 +279,16 @@ func TestListAuthors(t *testing.T) {
 			},
 			expected: []sqlc.Author{
 				{
-					Uuid: authorUuids[0],
-					Name: "author001",
-					Bio:  sql.NullString{String: "author001", Valid: true},
+					Uuid:    authorUuids[0],
+					Name:    "author001",
+					Bio:     sql.NullString{String: "author001", Valid: true},
+					Version: 1,
 				},
 				{
-					Uuid: authorUuids[1],
-					Name: "author002",
-					Bio:  sql.NullString{String: "author002", Valid: true},
+					Uuid:    authorUuids[1],
+					Name:    "author002",
+					Bio:     sql.NullString{String: "author002", Valid: true},
+					Version: 1,
 				},
 			},
 		},
@@ -316,6 +336,7 @@ func TestListAuthors(t *testing.T) {
 			)
 
 			for i := range authors {
+				checkTimestamps(t, &authors[i])
 				if authors[i] != tt.expected[i] {
 					t.Errorf("got=%v, want=%v", authors[i], tt.expected[i])
 				}
@@ -323,3 +344,104 @@ func TestListAuthors(t *testing.T) {
 		})
 	}
 }
+
+func TestRestoreAuthor(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			createAuthorParams sqlc.CreateAuthorParams
+			restoreAuthorUuid  binuuid.UUID
+		}
+		expected sqlc.Author
+	}{
+		{
+			scenario: "restore deleted author",
+			input: struct {
+				createAuthorParams sqlc.CreateAuthorParams
+				restoreAuthorUuid  binuuid.UUID
+			}{
+				createAuthorParams: sqlc.CreateAuthorParams{
+					Uuid: authorUuid,
+					Name: "author001",
+					Bio:  sql.NullString{String: "author001", Valid: true},
+				},
+				restoreAuthorUuid: authorUuid,
+			},
+			expected: sqlc.Author{
+				Uuid: authorUuid,
+				Name: "author001",
+				Bio:  sql.NullString{String: "author001", Valid: true},
+				// the delete and the restore are updates
+				Version: 3,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			queries := sqlc.New(db)
+
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			queries = queries.WithTx(tx)
+
+			// crete author
+			ctx := context.Background()
+			err = queries.CreateAuthor(ctx, tt.input.createAuthorParams)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// delete author
+			_, err = queries.DeleteAuthor(ctx, tt.input.restoreAuthorUuid)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// get deleted author
+			deleted, err := queries.GetAuthorIncludingDeleted(ctx, tt.input.restoreAuthorUuid)
+			if err != nil {
+				t.Error(err)
+			}
+			if !deleted.DeletedAt.Valid {
+				t.Errorf("got=%v, want deleted_at to be set", deleted.DeletedAt)
+			}
+
+			// restore author
+			n, err := queries.RestoreAuthor(ctx, tt.input.restoreAuthorUuid)
+			if err != nil {
+				t.Error(err)
+			}
+			if n != 1 {
+				t.Errorf("got=%v, want=%v", n, 1)
+			}
+
+			// get author
+			author, err := queries.GetAuthor(ctx, tt.input.restoreAuthorUuid)
+			if err != nil {
+				t.Error(err)
+			}
+
+			checkTimestamps(t, &author)
+			if author != tt.expected {
+				t.Errorf("got=%v, want=%v", author, tt.expected)
+			}
+		})
+	}
+}
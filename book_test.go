@@ -6,15 +6,19 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/google/uuid"
 )
 
 func TestCreateBook(t *testing.T) {
-	publisherUuid := uuid.New()
-	bookUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
@@ -44,6 +48,7 @@ func TestCreateBook(t *testing.T) {
 				Uuid:          bookUuid,
 				Title:         "book001",
 				PublisherUuid: publisherUuid,
+				Version:       1,
 			},
 		},
 	}
@@ -86,6 +91,7 @@ func TestCreateBook(t *testing.T) {
 				t.Error(err)
 			}
 
+			checkTimestamps(t, &book)
 			if book != tt.expected {
 				t.Errorf("got=%v, want=%v", book, tt.expected)
 			}
@@ -94,8 +100,11 @@ func TestCreateBook(t *testing.T) {
 }
 
 func TestUpdateBook(t *testing.T) {
-	publisherUuid := uuid.New()
-	bookUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
@@ -123,14 +132,16 @@ func TestUpdateBook(t *testing.T) {
 					PublisherUuid: publisherUuid,
 				},
 				updateBookParams: sqlc.UpdateBookParams{
-					Uuid:  bookUuid,
-					Title: "Updated: book001",
+					Uuid:    bookUuid,
+					Title:   "Updated: book001",
+					Version: 1,
 				},
 			},
 			expected: sqlc.Book{
 				Uuid:          bookUuid,
 				Title:         "Updated: book001",
 				PublisherUuid: publisherUuid,
+				Version:       2,
 			},
 		},
 	}
@@ -168,7 +179,7 @@ func TestUpdateBook(t *testing.T) {
 			}
 
 			// update book
-			err = queries.UpdateBook(ctx, tt.input.updateBookParams)
+			_, err = queries.UpdateBook(ctx, tt.input.updateBookParams)
 			if err != nil {
 				t.Error(err)
 			}
@@ -179,6 +190,7 @@ func TestUpdateBook(t *testing.T) {
 				t.Error(err)
 			}
 
+			checkTimestamps(t, &book)
 			if book != tt.expected {
 				t.Errorf("got=%v, want=%v", book, tt.expected)
 			}
@@ -187,15 +199,18 @@ func TestUpdateBook(t *testing.T) {
 }
 
 func TestDeleteBook(t *testing.T) {
-	publisherUuid := uuid.New()
-	bookUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
 		input    struct {
 			createPublisherParams sqlc.CreatePublisherParams
 			createBookParams      sqlc.CreateBookParams
-			deleteBookUuid        uuid.UUID
+			deleteBookUuid        binuuid.UUID
 		}
 		expected error
 	}{
@@ -204,7 +219,7 @@ func TestDeleteBook(t *testing.T) {
 			input: struct {
 				createPublisherParams sqlc.CreatePublisherParams
 				createBookParams      sqlc.CreateBookParams
-				deleteBookUuid        uuid.UUID
+				deleteBookUuid        binuuid.UUID
 			}{
 				createPublisherParams: sqlc.CreatePublisherParams{
 					Uuid: publisherUuid,
@@ -254,7 +269,7 @@ func TestDeleteBook(t *testing.T) {
 			}
 
 			// delete book
-			err = queries.DeleteBook(ctx, tt.input.deleteBookUuid)
+			_, err = queries.DeleteBook(ctx, tt.input.deleteBookUuid)
 			if err != nil {
 				t.Error(err)
 			}
@@ -269,10 +284,13 @@ func TestDeleteBook(t *testing.T) {
 }
 
 func TestListBooks(t *testing.T) {
-	publisherUuid := uuid.New()
-	bookUuids := []uuid.UUID{
-		uuid.New(),
-		uuid.New(),
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
 	}
 
 	tests := []struct {
@@ -311,11 +329,13 @@ func TestListBooks(t *testing.T) {
 					Uuid:          bookUuids[0],
 					Title:         "book001",
 					PublisherUuid: publisherUuid,
+					Version:       1,
 				},
 				{
 					Uuid:          bookUuids[1],
 					Title:         "book002",
 					PublisherUuid: publisherUuid,
+					Version:       1,
 				},
 			},
 		},
@@ -369,6 +389,7 @@ func TestListBooks(t *testing.T) {
 			)
 
 			for i := range books {
+				checkTimestamps(t, &books[i])
 				if books[i] != tt.expected[i] {
 					t.Errorf("got=%v, want=%v", books[i], tt.expected[i])
 				}
@@ -378,8 +399,11 @@ func TestListBooks(t *testing.T) {
 }
 
 func TestGetBookPublisher(t *testing.T) {
-	publisherUuid := uuid.New()
-	bookUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
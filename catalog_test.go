@@ -0,0 +1,1371 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestCatalogCreateBook(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			createBookParams catalog.CreateBookParams
+		}
+		expected catalog.BookDetail
+	}{
+		{
+			scenario: "create book with publisher and authors",
+			input: struct {
+				createBookParams catalog.CreateBookParams
+			}{
+				createBookParams: catalog.CreateBookParams{
+					Uuid:  bookUuid,
+					Title: "book001",
+					NewPublisher: &sqlc.CreatePublisherParams{
+						Uuid: publisherUuid,
+						Name: "publisher001",
+					},
+					NewAuthors: []sqlc.CreateAuthorParams{
+						{
+							Uuid: authorUuid,
+							Name: "author001",
+							Bio:  sql.NullString{String: "author001", Valid: true},
+						},
+					},
+				},
+			},
+			expected: catalog.BookDetail{
+				Book: sqlc.Book{
+					Uuid:          bookUuid,
+					Title:         "book001",
+					PublisherUuid: publisherUuid,
+					Version:       1,
+				},
+				Publisher: sqlc.Publisher{
+					Uuid:    publisherUuid,
+					Name:    "publisher001",
+					Version: 1,
+				},
+				Authors: []sqlc.Author{
+					{
+						Uuid:    authorUuid,
+						Name:    "author001",
+						Bio:     sql.NullString{String: "author001", Valid: true},
+						Version: 1,
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			service := catalog.New(db)
+
+			// create book
+			ctx := context.Background()
+			detail, err := service.CreateBook(ctx, tt.input.createBookParams)
+			if err != nil {
+				t.Error(err)
+			}
+
+			checkTimestamps(t, &detail.Book)
+			if detail.Book != tt.expected.Book {
+				t.Errorf("got=%v, want=%v", detail.Book, tt.expected.Book)
+			}
+			checkTimestamps(t, &detail.Publisher)
+			if detail.Publisher != tt.expected.Publisher {
+				t.Errorf("got=%v, want=%v", detail.Publisher, tt.expected.Publisher)
+			}
+			if len(detail.Authors) != len(tt.expected.Authors) {
+				t.Fatalf("got=%v, want=%v", detail.Authors, tt.expected.Authors)
+			}
+			for i := range detail.Authors {
+				checkTimestamps(t, &detail.Authors[i])
+				if detail.Authors[i] != tt.expected.Authors[i] {
+					t.Errorf("got=%v, want=%v", detail.Authors[i], tt.expected.Authors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCatalogCreateBookRollback(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+	bookUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			createBookParams catalog.CreateBookParams
+		}
+		expected struct {
+			createErr error
+			getErr    error
+		}
+	}{
+		{
+			scenario: "rollback when publisher does not exist",
+			input: struct {
+				createBookParams catalog.CreateBookParams
+			}{
+				createBookParams: catalog.CreateBookParams{
+					Uuid:          bookUuid,
+					Title:         "book001",
+					PublisherUuid: binuuid.New(),
+					NewAuthors: []sqlc.CreateAuthorParams{
+						{
+							Uuid: authorUuid,
+							Name: "author001",
+						},
+					},
+				},
+			},
+			expected: struct {
+				createErr error
+				getErr    error
+			}{
+				createErr: dberr.ErrReferenceMissing,
+				getErr:    sql.ErrNoRows,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			service := catalog.New(db)
+			queries := sqlc.New(db)
+
+			// create book
+			ctx := context.Background()
+			_, err := service.CreateBook(ctx, tt.input.createBookParams)
+			if !errors.Is(err, tt.expected.createErr) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.createErr)
+			}
+
+			// nothing is persisted
+			_, err = queries.GetBook(ctx, tt.input.createBookParams.Uuid)
+			if !errors.Is(err, tt.expected.getErr) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.getErr)
+			}
+			_, err = queries.GetAuthor(ctx, authorUuid)
+			if !errors.Is(err, tt.expected.getErr) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.getErr)
+			}
+		})
+	}
+}
+
+func TestCatalogInTxPanic(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+
+	// create author, then panic
+	ctx := context.Background()
+	func() {
+		defer func() {
+			if p := recover(); p != "panic001" {
+				t.Errorf("got=%v, want=%v", p, "panic001")
+			}
+		}()
+		_ = catalog.New(db).InTx(ctx, func(q *sqlc.Queries) error {
+			err := q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: authorUuid, Name: "author001"})
+			if err != nil {
+				t.Error(err)
+			}
+			panic("panic001")
+		})
+	}()
+
+	// the transaction is rolled back and its connection released
+	if inUse := db.Stats().InUse; inUse != 0 {
+		t.Errorf("got=%v, want=%v", inUse, 0)
+	}
+	_, err := sqlc.New(db).GetAuthor(ctx, authorUuid)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got=%v, want=%v", err, sql.ErrNoRows)
+	}
+}
+
+func TestCatalogAtomic(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		scenario string
+		// unsupported names a feature the scenario needs that
+		// go-mysql-server lacks, see testdb.Unsupported.
+		unsupported string
+		input       func(ctx context.Context, service *catalog.Service, first, second binuuid.UUID) error
+		expected    struct {
+			err       error
+			firstErr  error
+			secondErr error
+		}
+	}{
+		{
+			scenario: "commit",
+			input: func(ctx context.Context, service *catalog.Service, first, second binuuid.UUID) error {
+				err := service.Queries().CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: first, Name: "author001"})
+				if err != nil {
+					return err
+				}
+				return service.Queries().CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: second, Name: "author002"})
+			},
+			expected: struct {
+				err       error
+				firstErr  error
+				secondErr error
+			}{},
+		},
+		{
+			scenario: "rollback",
+			input: func(ctx context.Context, service *catalog.Service, first, second binuuid.UUID) error {
+				err := service.Queries().CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: first, Name: "author001"})
+				if err != nil {
+					return err
+				}
+				return errFailed
+			},
+			expected: struct {
+				err       error
+				firstErr  error
+				secondErr error
+			}{
+				err:       errFailed,
+				firstErr:  sql.ErrNoRows,
+				secondErr: sql.ErrNoRows,
+			},
+		},
+		{
+			scenario: "failed operation rolled back to its savepoint",
+			// the operations nest savepoints in the transaction
+			unsupported: "savepoints",
+			input: func(ctx context.Context, service *catalog.Service, first, second binuuid.UUID) error {
+				err := service.Queries().CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: first, Name: "author001"})
+				if err != nil {
+					return err
+				}
+				_, err = service.CreateBook(ctx, catalog.CreateBookParams{
+					Uuid:          binuuid.New(),
+					Title:         "book001",
+					PublisherUuid: binuuid.New(),
+					NewAuthors:    []sqlc.CreateAuthorParams{{Uuid: second, Name: "author002"}},
+				})
+				if !errors.Is(err, dberr.ErrReferenceMissing) {
+					return fmt.Errorf("got=%v, want=%v", err, dberr.ErrReferenceMissing)
+				}
+				return nil
+			},
+			expected: struct {
+				err       error
+				firstErr  error
+				secondErr error
+			}{
+				secondErr: sql.ErrNoRows,
+			},
+		},
+		{
+			scenario: "released operation rolled back with the transaction",
+			// the operations nest savepoints in the transaction
+			unsupported: "savepoints",
+			input: func(ctx context.Context, service *catalog.Service, first, second binuuid.UUID) error {
+				_, err := service.CreateBook(ctx, catalog.CreateBookParams{
+					Uuid:         binuuid.New(),
+					Title:        "book001",
+					NewPublisher: &sqlc.CreatePublisherParams{Uuid: binuuid.New(), Name: "publisher001"},
+					NewAuthors: []sqlc.CreateAuthorParams{
+						{Uuid: first, Name: "author001"},
+						{Uuid: second, Name: "author002"},
+					},
+				})
+				if err != nil {
+					return err
+				}
+				return errFailed
+			},
+			expected: struct {
+				err       error
+				firstErr  error
+				secondErr error
+			}{
+				err:       errFailed,
+				firstErr:  sql.ErrNoRows,
+				secondErr: sql.ErrNoRows,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+			if tt.unsupported != "" {
+				testdb.Unsupported(t, tt.unsupported)
+			}
+
+			service := catalog.New(db)
+			queries := sqlc.New(db)
+			first := binuuid.New()
+			second := binuuid.New()
+
+			ctx := context.Background()
+			err := service.Atomic(ctx, func(service *catalog.Service) error {
+				return tt.input(ctx, service, first, second)
+			})
+			if !errors.Is(err, tt.expected.err) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.err)
+			}
+
+			_, err = queries.GetAuthor(ctx, first)
+			if !errors.Is(err, tt.expected.firstErr) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.firstErr)
+			}
+			_, err = queries.GetAuthor(ctx, second)
+			if !errors.Is(err, tt.expected.secondErr) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.secondErr)
+			}
+		})
+	}
+}
+
+func TestCatalogListAuthors(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
+		binuuid.New(),
+	}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			createAuthorParamsList []sqlc.CreateAuthorParams
+			limit                  int
+		}
+		expected [][]binuuid.UUID
+	}{
+		{
+			scenario: "list authors page by page",
+			input: struct {
+				createAuthorParamsList []sqlc.CreateAuthorParams
+				limit                  int
+			}{
+				createAuthorParamsList: []sqlc.CreateAuthorParams{
+					{
+						Uuid: authorUuids[0],
+						Name: "author001",
+					},
+					{
+						Uuid: authorUuids[1],
+						Name: "author002",
+					},
+					{
+						Uuid: authorUuids[2],
+						Name: "author003",
+					},
+				},
+				limit: 2,
+			},
+			expected: func() [][]binuuid.UUID {
+				sorted := append([]binuuid.UUID{}, authorUuids...)
+				sort.Slice(sorted, func(i, j int) bool {
+					return sorted[i].String() < sorted[j].String()
+				})
+				return [][]binuuid.UUID{sorted[:2], sorted[2:]}
+			}(),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create author
+			ctx := context.Background()
+			for _, params := range tt.input.createAuthorParamsList {
+				err := service.Queries().CreateAuthor(ctx, params)
+				if err != nil {
+					t.Error(err)
+				}
+			}
+
+			// list authors
+			req := pagination.Request{Limit: tt.input.limit}
+			for i, expected := range tt.expected {
+				page, err := service.ListAuthors(ctx, req, catalog.ListFilter{})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(page.Items) != len(expected) {
+					t.Fatalf("page %d: got=%v, want=%v", i, page.Items, expected)
+				}
+				for j := range page.Items {
+					if page.Items[j].Uuid != expected[j] {
+						t.Errorf("page %d: got=%v, want=%v", i, page.Items[j].Uuid, expected[j])
+					}
+				}
+
+				last := i == len(tt.expected)-1
+				if (page.NextCursor == "") != last {
+					t.Errorf("page %d: got next cursor %q", i, page.NextCursor)
+				}
+				req.Cursor = page.NextCursor
+			}
+		})
+	}
+}
+
+func TestCatalogListAuthorsFilter(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	now := time.Now()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			createAuthorParams sqlc.CreateAuthorParams
+			filter             catalog.ListFilter
+		}
+		expected int
+	}{
+		{
+			scenario: "created within range",
+			input: struct {
+				createAuthorParams sqlc.CreateAuthorParams
+				filter             catalog.ListFilter
+			}{
+				createAuthorParams: sqlc.CreateAuthorParams{
+					Uuid: binuuid.New(),
+					Name: "author001",
+				},
+				filter: catalog.ListFilter{
+					Created: catalog.TimeRange{From: now.Add(-time.Hour), To: now.Add(time.Hour)},
+				},
+			},
+			expected: 1,
+		},
+		{
+			scenario: "created before range",
+			input: struct {
+				createAuthorParams sqlc.CreateAuthorParams
+				filter             catalog.ListFilter
+			}{
+				createAuthorParams: sqlc.CreateAuthorParams{
+					Uuid: binuuid.New(),
+					Name: "author001",
+				},
+				filter: catalog.ListFilter{
+					Created: catalog.TimeRange{To: now.Add(-time.Hour)},
+				},
+			},
+			expected: 0,
+		},
+		{
+			scenario: "updated after range",
+			input: struct {
+				createAuthorParams sqlc.CreateAuthorParams
+				filter             catalog.ListFilter
+			}{
+				createAuthorParams: sqlc.CreateAuthorParams{
+					Uuid: binuuid.New(),
+					Name: "author001",
+				},
+				filter: catalog.ListFilter{
+					Updated: catalog.TimeRange{From: now.Add(time.Hour)},
+				},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create author
+			ctx := context.Background()
+			err = service.Queries().CreateAuthor(ctx, tt.input.createAuthorParams)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// list authors
+			page, err := service.ListAuthors(ctx, pagination.Request{}, tt.input.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(page.Items) != tt.expected {
+				t.Errorf("got=%v, want=%v", len(page.Items), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalogDeleteInUse(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			delete func(ctx context.Context, service *catalog.Service) error
+		}
+		expected error
+	}{
+		{
+			scenario: "delete author with books",
+			input: struct {
+				delete func(ctx context.Context, service *catalog.Service) error
+			}{
+				delete: func(ctx context.Context, service *catalog.Service) error {
+					return service.DeleteAuthor(ctx, authorUuid)
+				},
+			},
+			expected: nil,
+		},
+		{
+			scenario: "delete book with authors",
+			input: struct {
+				delete func(ctx context.Context, service *catalog.Service) error
+			}{
+				delete: func(ctx context.Context, service *catalog.Service) error {
+					return service.DeleteBook(ctx, bookUuid)
+				},
+			},
+			expected: nil,
+		},
+		{
+			scenario: "delete publisher with books",
+			input: struct {
+				delete func(ctx context.Context, service *catalog.Service) error
+			}{
+				delete: func(ctx context.Context, service *catalog.Service) error {
+					return service.DeletePublisher(ctx, publisherUuid)
+				},
+			},
+			expected: dberr.ErrInUse,
+		},
+		{
+			scenario: "delete missing author",
+			input: struct {
+				delete func(ctx context.Context, service *catalog.Service) error
+			}{
+				delete: func(ctx context.Context, service *catalog.Service) error {
+					return service.DeleteAuthor(ctx, binuuid.New())
+				},
+			},
+			expected: dberr.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create book
+			ctx := context.Background()
+			_, err = service.CreateBook(ctx, catalog.CreateBookParams{
+				Uuid:         bookUuid,
+				Title:        "book001",
+				NewPublisher: &sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"},
+				NewAuthors:   []sqlc.CreateAuthorParams{{Uuid: authorUuid, Name: "author001"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// delete
+			err = tt.input.delete(ctx, service)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalogDeleteRestoreLinks(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			delete  func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error
+			restore func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error)
+		}
+		expected error
+	}{
+		{
+			scenario: "restored author keeps links",
+			input: struct {
+				delete  func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error
+				restore func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error)
+			}{
+				delete: func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error {
+					return service.DeleteAuthor(ctx, author)
+				},
+				restore: func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error) {
+					return q.RestoreAuthor(ctx, author)
+				},
+			},
+			expected: nil,
+		},
+		{
+			scenario: "restored book keeps links",
+			input: struct {
+				delete  func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error
+				restore func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error)
+			}{
+				delete: func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error {
+					return service.DeleteBook(ctx, book)
+				},
+				restore: func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error) {
+					return q.RestoreBook(ctx, book)
+				},
+			},
+			expected: nil,
+		},
+		{
+			scenario: "cascade removes links for good",
+			input: struct {
+				delete  func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error
+				restore func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error)
+			}{
+				delete: func(ctx context.Context, service *catalog.Service, author, book binuuid.UUID) error {
+					_, err := service.DeleteAuthorCascade(ctx, author)
+					return err
+				},
+				restore: func(ctx context.Context, q *sqlc.Queries, author, book binuuid.UUID) (int64, error) {
+					return q.RestoreAuthor(ctx, author)
+				},
+			},
+			expected: sql.ErrNoRows,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			service := catalog.New(db)
+			queries := sqlc.New(db)
+			authorUuid := binuuid.New()
+			bookUuid := binuuid.New()
+			link := sqlc.GetAuthorBookParams{AuthorUuid: authorUuid, BookUuid: bookUuid}
+
+			// create book
+			ctx := context.Background()
+			_, err := service.CreateBook(ctx, catalog.CreateBookParams{
+				Uuid:         bookUuid,
+				Title:        "book001",
+				NewPublisher: &sqlc.CreatePublisherParams{Uuid: binuuid.New(), Name: "publisher001"},
+				NewAuthors:   []sqlc.CreateAuthorParams{{Uuid: authorUuid, Name: "author001"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// the link is hidden while the author or book is deleted
+			err = tt.input.delete(ctx, service, authorUuid, bookUuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = queries.GetAuthorBook(ctx, link)
+			if !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("got=%v, want=%v", err, sql.ErrNoRows)
+			}
+
+			// and back after the restore, unless it was removed
+			n, err := tt.input.restore(ctx, queries, authorUuid, bookUuid)
+			if err != nil || n != 1 {
+				t.Fatalf("got=%v, %v, want=%v", n, err, 1)
+			}
+			_, err = queries.GetAuthorBook(ctx, link)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalogPurge(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "correlated subqueries in DELETE")
+	db := testdb.New(t)
+
+	now := time.Now()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			deletedBefore time.Time
+		}
+		expected catalog.PurgeResult
+	}{
+		{
+			scenario: "purge rows deleted before cutoff",
+			input: struct {
+				deletedBefore time.Time
+			}{
+				deletedBefore: now.Add(time.Hour),
+			},
+			expected: catalog.PurgeResult{
+				AuthorBooks: 1,
+				Books:       1,
+				Authors:     2,
+				Publishers:  1,
+			},
+		},
+		{
+			scenario: "keep rows deleted after cutoff",
+			input: struct {
+				deletedBefore time.Time
+			}{
+				deletedBefore: now.Add(-time.Hour),
+			},
+			expected: catalog.PurgeResult{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+			queries := service.Queries()
+
+			// create book
+			ctx := context.Background()
+			authorUuid := binuuid.New()
+			publisherUuid := binuuid.New()
+			bookUuid := binuuid.New()
+			_, err = service.CreateBook(ctx, catalog.CreateBookParams{
+				Uuid:         bookUuid,
+				Title:        "book001",
+				NewPublisher: &sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"},
+				NewAuthors:   []sqlc.CreateAuthorParams{{Uuid: authorUuid, Name: "author001"}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			lonelyAuthorUuid := binuuid.New()
+			err = queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: lonelyAuthorUuid, Name: "author002"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// soft-delete everything, bypassing the in-use checks
+			for _, del := range []struct {
+				fn   func(context.Context, binuuid.UUID) (int64, error)
+				uuid binuuid.UUID
+			}{
+				{queries.DeleteBook, bookUuid},
+				{queries.DeleteAuthor, authorUuid},
+				{queries.DeleteAuthor, lonelyAuthorUuid},
+				{queries.DeletePublisher, publisherUuid},
+			} {
+				_, err = del.fn(ctx, del.uuid)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// purge
+			result, err := service.Purge(ctx, tt.input.deletedBefore)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("got=%+v, want=%+v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCatalogDeleteAuthorCascade(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			bookCount int
+		}
+		expected catalog.DeleteAuthorResult
+	}{
+		{
+			scenario: "delete author with books",
+			input: struct {
+				bookCount int
+			}{
+				bookCount: 2,
+			},
+			expected: catalog.DeleteAuthorResult{AuthorBooks: 2},
+		},
+		{
+			scenario: "delete author without books",
+			input: struct {
+				bookCount int
+			}{
+				bookCount: 0,
+			},
+			expected: catalog.DeleteAuthorResult{AuthorBooks: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+			queries := service.Queries()
+
+			// create author and books
+			ctx := context.Background()
+			err = queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: authorUuid, Name: "author001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			publisherUuid := binuuid.New()
+			err = queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < tt.input.bookCount; i++ {
+				_, err = service.CreateBook(ctx, catalog.CreateBookParams{
+					Uuid:          binuuid.New(),
+					Title:         "book001",
+					PublisherUuid: publisherUuid,
+					AuthorUuids:   []binuuid.UUID{authorUuid},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// delete author
+			result, err := service.DeleteAuthorCascade(ctx, authorUuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != tt.expected {
+				t.Errorf("got=%+v, want=%+v", result, tt.expected)
+			}
+			if _, err := queries.GetAuthor(ctx, authorUuid); !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("got=%v, want=%v", err, sql.ErrNoRows)
+			}
+		})
+	}
+}
+
+func TestCatalogDeletePublisherReassign(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	fromUuid := binuuid.New()
+	toUuid := binuuid.New()
+	bookUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			to binuuid.UUID
+		}
+		expected struct {
+			result catalog.DeletePublisherResult
+			err    error
+		}
+	}{
+		{
+			scenario: "reassign books",
+			input: struct {
+				to binuuid.UUID
+			}{
+				to: toUuid,
+			},
+			expected: struct {
+				result catalog.DeletePublisherResult
+				err    error
+			}{
+				result: catalog.DeletePublisherResult{Books: 1},
+			},
+		},
+		{
+			scenario: "reassign books to missing publisher",
+			input: struct {
+				to binuuid.UUID
+			}{
+				to: binuuid.New(),
+			},
+			expected: struct {
+				result catalog.DeletePublisherResult
+				err    error
+			}{
+				err: dberr.ErrReferenceMissing,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+			queries := service.Queries()
+
+			// create publishers and book
+			ctx := context.Background()
+			for _, params := range []sqlc.CreatePublisherParams{
+				{Uuid: fromUuid, Name: "publisher001"},
+				{Uuid: toUuid, Name: "publisher002"},
+			} {
+				err = queries.CreatePublisher(ctx, params)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			err = queries.CreateBook(ctx, sqlc.CreateBookParams{Uuid: bookUuid, Title: "book001", PublisherUuid: fromUuid})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// delete publisher
+			result, err := service.DeletePublisherReassign(ctx, fromUuid, tt.input.to)
+			if !errors.Is(err, tt.expected.err) {
+				t.Fatalf("got=%v, want=%v", err, tt.expected.err)
+			}
+			if result != tt.expected.result {
+				t.Errorf("got=%+v, want=%+v", result, tt.expected.result)
+			}
+
+			// get book publisher
+			book, err := queries.GetBook(ctx, bookUuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := fromUuid
+			if tt.expected.err == nil {
+				want = toUuid
+			}
+			if book.PublisherUuid != want {
+				t.Errorf("got=%v, want=%v", book.PublisherUuid, want)
+			}
+		})
+	}
+}
+
+func TestCatalogUpdateAuthor(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			updateAuthorParams sqlc.UpdateAuthorParams
+		}
+		expected struct {
+			author sqlc.Author
+			err    error
+		}
+	}{
+		{
+			scenario: "update current version",
+			input: struct {
+				updateAuthorParams sqlc.UpdateAuthorParams
+			}{
+				updateAuthorParams: sqlc.UpdateAuthorParams{
+					Uuid:    authorUuid,
+					Name:    "Updated: author001",
+					Version: 1,
+				},
+			},
+			expected: struct {
+				author sqlc.Author
+				err    error
+			}{
+				author: sqlc.Author{
+					Uuid:    authorUuid,
+					Name:    "Updated: author001",
+					Version: 2,
+				},
+			},
+		},
+		{
+			scenario: "update stale version",
+			input: struct {
+				updateAuthorParams sqlc.UpdateAuthorParams
+			}{
+				updateAuthorParams: sqlc.UpdateAuthorParams{
+					Uuid:    authorUuid,
+					Name:    "Updated: author001",
+					Version: 0,
+				},
+			},
+			expected: struct {
+				author sqlc.Author
+				err    error
+			}{
+				err: dberr.ErrConflict,
+			},
+		},
+		{
+			scenario: "update missing author",
+			input: struct {
+				updateAuthorParams sqlc.UpdateAuthorParams
+			}{
+				updateAuthorParams: sqlc.UpdateAuthorParams{
+					Uuid:    binuuid.New(),
+					Name:    "Updated: author001",
+					Version: 1,
+				},
+			},
+			expected: struct {
+				author sqlc.Author
+				err    error
+			}{
+				err: dberr.ErrNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create author
+			ctx := context.Background()
+			err = service.Queries().CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: authorUuid, Name: "author001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// update author
+			author, err := service.UpdateAuthor(ctx, tt.input.updateAuthorParams)
+			if !errors.Is(err, tt.expected.err) {
+				t.Fatalf("got=%v, want=%v", err, tt.expected.err)
+			}
+			if err != nil {
+				return
+			}
+
+			checkTimestamps(t, &author)
+			if author != tt.expected.author {
+				t.Errorf("got=%v, want=%v", author, tt.expected.author)
+			}
+		})
+	}
+}
+
+func TestCatalogBulkCreateAuthors(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	existingUuid := binuuid.New()
+	newUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+
+	tests := []struct {
+		scenario string
+		input    []sqlc.CreateAuthorParams
+		expected struct {
+			inserted int
+			failed   []int
+		}
+	}{
+		{
+			scenario: "all rows inserted",
+			input: []sqlc.CreateAuthorParams{
+				{Uuid: newUuids[0], Name: "author001"},
+				{Uuid: newUuids[1], Name: "author002"},
+			},
+			expected: struct {
+				inserted int
+				failed   []int
+			}{
+				inserted: 2,
+			},
+		},
+		{
+			scenario: "duplicate rows rejected",
+			input: []sqlc.CreateAuthorParams{
+				{Uuid: newUuids[0], Name: "author001"},
+				{Uuid: existingUuid, Name: "author002"},
+				{Uuid: newUuids[1], Name: "author003"},
+				{Uuid: newUuids[0], Name: "author004"},
+			},
+			expected: struct {
+				inserted int
+				failed   []int
+			}{
+				inserted: 2,
+				failed:   []int{1, 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create existing author
+			ctx := context.Background()
+			err = service.Queries().CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: existingUuid, Name: "existing"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// bulk create authors
+			result, err := service.BulkCreateAuthors(ctx, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Inserted != tt.expected.inserted {
+				t.Errorf("got=%v, want=%v", result.Inserted, tt.expected.inserted)
+			}
+			if len(result.Failures) != len(tt.expected.failed) {
+				t.Fatalf("got=%v, want=%v", result.Failures, tt.expected.failed)
+			}
+			for i, failure := range result.Failures {
+				if failure.Index != tt.expected.failed[i] {
+					t.Errorf("got=%v, want=%v", failure.Index, tt.expected.failed[i])
+				}
+				if !errors.Is(failure, dberr.ErrAlreadyExists) {
+					t.Errorf("got=%v, want=%v", failure.Err, dberr.ErrAlreadyExists)
+				}
+			}
+			for _, authorUuid := range newUuids {
+				if _, err := service.Queries().GetAuthor(ctx, authorUuid); err != nil {
+					t.Errorf("got=%v, want=%v", err, nil)
+				}
+			}
+		})
+	}
+}
+
+func TestCatalogBulkCreateBooks(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    []sqlc.CreateBookParams
+		expected struct {
+			inserted int
+			failed   []int
+		}
+	}{
+		{
+			scenario: "missing publisher rejected",
+			input: []sqlc.CreateBookParams{
+				{Uuid: binuuid.New(), Title: "book001", PublisherUuid: publisherUuid},
+				{Uuid: binuuid.New(), Title: "book002", PublisherUuid: binuuid.New()},
+				{Uuid: binuuid.New(), Title: "book003", PublisherUuid: publisherUuid},
+			},
+			expected: struct {
+				inserted int
+				failed   []int
+			}{
+				inserted: 2,
+				failed:   []int{1},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create publisher
+			ctx := context.Background()
+			err = service.Queries().CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// bulk create books
+			result, err := service.BulkCreateBooks(ctx, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Inserted != tt.expected.inserted {
+				t.Errorf("got=%v, want=%v", result.Inserted, tt.expected.inserted)
+			}
+			if len(result.Failures) != len(tt.expected.failed) {
+				t.Fatalf("got=%v, want=%v", result.Failures, tt.expected.failed)
+			}
+			for i, failure := range result.Failures {
+				if failure.Index != tt.expected.failed[i] {
+					t.Errorf("got=%v, want=%v", failure.Index, tt.expected.failed[i])
+				}
+				if !errors.Is(failure, dberr.ErrReferenceMissing) {
+					t.Errorf("got=%v, want=%v", failure.Err, dberr.ErrReferenceMissing)
+				}
+			}
+		})
+	}
+}
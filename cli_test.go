@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/cli"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestCLIAuthor(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+
+	type output struct {
+		Uuid    binuuid.UUID `json:"uuid"`
+		Name    string       `json:"name"`
+		Bio     *string      `json:"bio"`
+		Version uint32       `json:"version"`
+	}
+
+	bio := "bio001"
+	tests := []struct {
+		scenario string
+		input    []string
+		expected struct {
+			output *output
+			err    error
+		}
+	}{
+		{
+			scenario: "create author",
+			input:    []string{"author", "create", "-uuid", authorUuid.String(), "-name", "author001", "-bio", "bio001", "-output", "json"},
+			expected: struct {
+				output *output
+				err    error
+			}{
+				output: &output{Uuid: authorUuid, Name: "author001", Bio: &bio, Version: 1},
+			},
+		},
+		{
+			scenario: "update name keeps bio",
+			input:    []string{"author", "update", authorUuid.String(), "-name", "author002", "-output", "json"},
+			expected: struct {
+				output *output
+				err    error
+			}{
+				output: &output{Uuid: authorUuid, Name: "author002", Bio: &bio, Version: 2},
+			},
+		},
+		{
+			scenario: "update stale version",
+			input:    []string{"author", "update", authorUuid.String(), "-name", "author003", "-version", "1"},
+			expected: struct {
+				output *output
+				err    error
+			}{
+				err: dberr.ErrConflict,
+			},
+		},
+		{
+			scenario: "delete author",
+			input:    []string{"author", "delete", authorUuid.String()},
+		},
+		{
+			scenario: "get deleted author",
+			input:    []string{"author", "get", authorUuid.String()},
+			expected: struct {
+				output *output
+				err    error
+			}{
+				err: dberr.ErrNotFound,
+			},
+		},
+	}
+
+	// test with transaction
+	tx, err := db.Begin()
+	if err != nil {
+		t.Error(err)
+	}
+	t.Cleanup(func() {
+		err = tx.Rollback()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	// the scenarios build on each other
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			var stdout strings.Builder
+			err := cli.New(tx, &stdout).Run(context.Background(), tt.input)
+			if !errors.Is(err, tt.expected.err) {
+				t.Fatalf("got=%v, want=%v", err, tt.expected.err)
+			}
+			if tt.expected.output == nil {
+				return
+			}
+
+			var got output
+			if err := json.Unmarshal([]byte(stdout.String()), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Uuid != tt.expected.output.Uuid || got.Name != tt.expected.output.Name ||
+				*got.Bio != *tt.expected.output.Bio || got.Version != tt.expected.output.Version {
+				t.Errorf("got=%v, want=%v", got, *tt.expected.output)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+ALTER TABLE `author_books`
+  DROP FOREIGN KEY `author_books_ibfk_1`,
+  DROP FOREIGN KEY `author_books_ibfk_2`;
+
+ALTER TABLE `books`
+  DROP FOREIGN KEY `books_ibfk_1`;
+
+ALTER TABLE `authors`
+  MODIFY `uuid` VARBINARY(36) NOT NULL;
+
+ALTER TABLE `publishers`
+  MODIFY `uuid` VARBINARY(36) NOT NULL;
+
+ALTER TABLE `books`
+  MODIFY `uuid` VARBINARY(36) NOT NULL,
+  MODIFY `publisher_uuid` VARBINARY(36) NOT NULL;
+
+ALTER TABLE `author_books`
+  MODIFY `author_uuid` VARBINARY(36) NOT NULL,
+  MODIFY `book_uuid` VARBINARY(36) NOT NULL;
+
+UPDATE `authors`
+SET
+  `uuid` = BIN_TO_UUID(`uuid`);
+
+UPDATE `publishers`
+SET
+  `uuid` = BIN_TO_UUID(`uuid`);
+
+UPDATE `books`
+SET
+  `uuid` = BIN_TO_UUID(`uuid`),
+  `publisher_uuid` = BIN_TO_UUID(`publisher_uuid`);
+
+UPDATE `author_books`
+SET
+  `author_uuid` = BIN_TO_UUID(`author_uuid`),
+  `book_uuid` = BIN_TO_UUID(`book_uuid`);
+
+ALTER TABLE `books`
+  ADD CONSTRAINT `books_ibfk_1` FOREIGN KEY (`publisher_uuid`) REFERENCES `publishers` (`uuid`);
+
+ALTER TABLE `author_books`
+  ADD CONSTRAINT `author_books_ibfk_1` FOREIGN KEY (`author_uuid`) REFERENCES `authors` (`uuid`),
+  ADD CONSTRAINT `author_books_ibfk_2` FOREIGN KEY (`book_uuid`) REFERENCES `books` (`uuid`);
@@ -0,0 +1,45 @@
+ALTER TABLE `author_books`
+  DROP FOREIGN KEY `author_books_ibfk_1`,
+  DROP FOREIGN KEY `author_books_ibfk_2`;
+
+ALTER TABLE `books`
+  DROP FOREIGN KEY `books_ibfk_1`;
+
+UPDATE `authors`
+SET
+  `uuid` = UUID_TO_BIN(`uuid`);
+
+UPDATE `publishers`
+SET
+  `uuid` = UUID_TO_BIN(`uuid`);
+
+UPDATE `books`
+SET
+  `uuid` = UUID_TO_BIN(`uuid`),
+  `publisher_uuid` = UUID_TO_BIN(`publisher_uuid`);
+
+UPDATE `author_books`
+SET
+  `author_uuid` = UUID_TO_BIN(`author_uuid`),
+  `book_uuid` = UUID_TO_BIN(`book_uuid`);
+
+ALTER TABLE `authors`
+  MODIFY `uuid` BINARY(16) NOT NULL;
+
+ALTER TABLE `publishers`
+  MODIFY `uuid` BINARY(16) NOT NULL;
+
+ALTER TABLE `books`
+  MODIFY `uuid` BINARY(16) NOT NULL,
+  MODIFY `publisher_uuid` BINARY(16) NOT NULL;
+
+ALTER TABLE `author_books`
+  MODIFY `author_uuid` BINARY(16) NOT NULL,
+  MODIFY `book_uuid` BINARY(16) NOT NULL;
+
+ALTER TABLE `books`
+  ADD CONSTRAINT `books_ibfk_1` FOREIGN KEY (`publisher_uuid`) REFERENCES `publishers` (`uuid`);
+
+ALTER TABLE `author_books`
+  ADD CONSTRAINT `author_books_ibfk_1` FOREIGN KEY (`author_uuid`) REFERENCES `authors` (`uuid`),
+  ADD CONSTRAINT `author_books_ibfk_2` FOREIGN KEY (`book_uuid`) REFERENCES `books` (`uuid`);
@@ -0,0 +1,23 @@
+ALTER TABLE `author_books`
+  DROP INDEX `idx_author_books_updated_at`,
+  DROP INDEX `idx_author_books_created_at`,
+  DROP COLUMN `updated_at`,
+  DROP COLUMN `created_at`;
+
+ALTER TABLE `books`
+  DROP INDEX `idx_books_updated_at`,
+  DROP INDEX `idx_books_created_at`,
+  DROP COLUMN `updated_at`,
+  DROP COLUMN `created_at`;
+
+ALTER TABLE `publishers`
+  DROP INDEX `idx_publishers_updated_at`,
+  DROP INDEX `idx_publishers_created_at`,
+  DROP COLUMN `updated_at`,
+  DROP COLUMN `created_at`;
+
+ALTER TABLE `authors`
+  DROP INDEX `idx_authors_updated_at`,
+  DROP INDEX `idx_authors_created_at`,
+  DROP COLUMN `updated_at`,
+  DROP COLUMN `created_at`;
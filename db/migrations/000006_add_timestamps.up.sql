@@ -0,0 +1,23 @@
+ALTER TABLE `authors`
+  ADD COLUMN `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  ADD COLUMN `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  ADD INDEX `idx_authors_created_at` (`created_at`),
+  ADD INDEX `idx_authors_updated_at` (`updated_at`);
+
+ALTER TABLE `publishers`
+  ADD COLUMN `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  ADD COLUMN `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  ADD INDEX `idx_publishers_created_at` (`created_at`),
+  ADD INDEX `idx_publishers_updated_at` (`updated_at`);
+
+ALTER TABLE `books`
+  ADD COLUMN `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  ADD COLUMN `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  ADD INDEX `idx_books_created_at` (`created_at`),
+  ADD INDEX `idx_books_updated_at` (`updated_at`);
+
+ALTER TABLE `author_books`
+  ADD COLUMN `created_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  ADD COLUMN `updated_at` DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6),
+  ADD INDEX `idx_author_books_created_at` (`created_at`),
+  ADD INDEX `idx_author_books_updated_at` (`updated_at`);
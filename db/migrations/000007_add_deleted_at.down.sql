@@ -0,0 +1,11 @@
+ALTER TABLE `books`
+  DROP INDEX `idx_books_deleted_at`,
+  DROP COLUMN `deleted_at`;
+
+ALTER TABLE `publishers`
+  DROP INDEX `idx_publishers_deleted_at`,
+  DROP COLUMN `deleted_at`;
+
+ALTER TABLE `authors`
+  DROP INDEX `idx_authors_deleted_at`,
+  DROP COLUMN `deleted_at`;
@@ -0,0 +1,11 @@
+ALTER TABLE `authors`
+  ADD COLUMN `deleted_at` DATETIME(6) NULL DEFAULT NULL,
+  ADD INDEX `idx_authors_deleted_at` (`deleted_at`);
+
+ALTER TABLE `publishers`
+  ADD COLUMN `deleted_at` DATETIME(6) NULL DEFAULT NULL,
+  ADD INDEX `idx_publishers_deleted_at` (`deleted_at`);
+
+ALTER TABLE `books`
+  ADD COLUMN `deleted_at` DATETIME(6) NULL DEFAULT NULL,
+  ADD INDEX `idx_books_deleted_at` (`deleted_at`);
@@ -0,0 +1,8 @@
+ALTER TABLE `books`
+  DROP COLUMN `version`;
+
+ALTER TABLE `publishers`
+  DROP COLUMN `version`;
+
+ALTER TABLE `authors`
+  DROP COLUMN `version`;
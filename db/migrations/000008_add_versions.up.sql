@@ -0,0 +1,8 @@
+ALTER TABLE `authors`
+  ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 1;
+
+ALTER TABLE `publishers`
+  ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 1;
+
+ALTER TABLE `books`
+  ADD COLUMN `version` INT UNSIGNED NOT NULL DEFAULT 1;
@@ -0,0 +1,5 @@
+ALTER TABLE `authors`
+  DROP INDEX `ft_authors_name_bio`;
+
+ALTER TABLE `books`
+  DROP INDEX `ft_books_title`;
@@ -0,0 +1,5 @@
+ALTER TABLE `books`
+  ADD FULLTEXT INDEX `ft_books_title` (`title`);
+
+ALTER TABLE `authors`
+  ADD FULLTEXT INDEX `ft_authors_name_bio` (`name`, `bio`);
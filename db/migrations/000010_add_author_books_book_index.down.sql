@@ -0,0 +1,8 @@
+ALTER TABLE `author_books`
+  DROP FOREIGN KEY `author_books_ibfk_2`;
+
+ALTER TABLE `author_books`
+  DROP INDEX `idx_author_books_book_uuid`;
+
+ALTER TABLE `author_books`
+  ADD CONSTRAINT `author_books_ibfk_2` FOREIGN KEY (`book_uuid`) REFERENCES `books` (`uuid`);
@@ -0,0 +1,2 @@
+ALTER TABLE `author_books`
+  ADD INDEX `idx_author_books_book_uuid` (`book_uuid`, `author_uuid`);
@@ -0,0 +1,10 @@
+// Package migrations embeds the schema migrations, so that the binary can
+// apply them without the source tree; see package internal/migration.
+package migrations
+
+import "embed"
+
+// FS holds the golang-migrate files NNNNNN_name.{up,down}.sql.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -1,11 +1,15 @@
 -- name: GetAuthorBook :one
 SELECT
-  *
+  ab.*
 FROM
-  author_books
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+  INNER JOIN books AS b ON ab.book_uuid = b.uuid
 WHERE
-  author_uuid = ?
-  AND book_uuid = ?
+  ab.author_uuid = ?
+  AND ab.book_uuid = ?
+  AND a.deleted_at IS NULL
+  AND b.deleted_at IS NULL
 LIMIT
   1;
 
@@ -20,6 +24,9 @@ FROM
   authors AS a
   INNER JOIN author_books AS ab ON a.uuid = ab.author_uuid
   INNER JOIN books AS b ON ab.book_uuid = b.uuid
+WHERE
+  a.deleted_at IS NULL
+  AND b.deleted_at IS NULL
 ORDER BY
   a.uuid,
   b.uuid;
@@ -30,8 +37,82 @@ INSERT INTO
 VALUES
   (?, ?);
 
--- name: DeleteAuthorBook :exec
+-- name: DeleteAuthorBook :execrows
 DELETE FROM author_books
 WHERE
   author_uuid = ?
   AND book_uuid = ?;
+
+-- name: PurgeAuthorBooksOfAuthors :execrows
+DELETE FROM author_books
+WHERE
+  author_uuid IN (
+    SELECT
+      authors.uuid
+    FROM
+      authors
+    WHERE
+      authors.deleted_at < CAST(sqlc.arg(deleted_before) AS DATETIME(6))
+  );
+
+-- name: PurgeAuthorBooksOfBooks :execrows
+DELETE FROM author_books
+WHERE
+  book_uuid IN (
+    SELECT
+      books.uuid
+    FROM
+      books
+    WHERE
+      books.deleted_at < CAST(sqlc.arg(deleted_before) AS DATETIME(6))
+  );
+
+-- name: DeleteAuthorBooksByAuthor :execrows
+DELETE FROM author_books
+WHERE
+  author_uuid = ?;
+
+-- name: ListBooksByAuthor :many
+SELECT
+  b.*
+FROM
+  author_books AS ab
+  INNER JOIN books AS b ON ab.book_uuid = b.uuid
+WHERE
+  ab.author_uuid = sqlc.arg(author_uuid)
+  AND b.uuid > sqlc.arg(after_uuid)
+  AND b.deleted_at IS NULL
+ORDER BY
+  b.uuid
+LIMIT
+  ?;
+
+-- name: ListAuthorsByBook :many
+SELECT
+  a.*
+FROM
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+WHERE
+  ab.book_uuid = sqlc.arg(book_uuid)
+  AND a.uuid > sqlc.arg(after_uuid)
+  AND a.deleted_at IS NULL
+ORDER BY
+  a.uuid
+LIMIT
+  ?;
+
+-- name: ListAuthorNamesOfBooks :many
+SELECT
+  ab.book_uuid,
+  a.name AS author_name
+FROM
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+WHERE
+  ab.book_uuid IN (sqlc.slice(book_uuids))
+  AND a.deleted_at IS NULL
+ORDER BY
+  ab.book_uuid,
+  a.name,
+  a.uuid;
@@ -1,4 +1,15 @@
 -- name: GetAuthor :one
+SELECT
+  *
+FROM
+  authors
+WHERE
+  uuid = ?
+  AND deleted_at IS NULL
+LIMIT
+  1;
+
+-- name: GetAuthorIncludingDeleted :one
 SELECT
   *
 FROM
@@ -13,6 +24,8 @@ SELECT
   *
 FROM
   authors
+WHERE
+  deleted_at IS NULL
 ORDER BY
   uuid;
 
@@ -22,15 +35,69 @@ INSERT INTO
 VALUES
   (?, ?, ?);
 
--- name: UpdateAuthor :exec
+-- name: UpdateAuthor :execrows
 UPDATE authors
 SET
   name = ?,
-  bio = ?
+  bio = ?,
+  version = version + 1
 WHERE
-  uuid = ?;
+  uuid = ?
+  AND version = ?
+  AND deleted_at IS NULL;
 
--- name: DeleteAuthor :exec
+-- name: DeleteAuthor :execrows
+UPDATE authors
+SET
+  deleted_at = CURRENT_TIMESTAMP(6),
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NULL;
+
+-- name: RestoreAuthor :execrows
+UPDATE authors
+SET
+  deleted_at = NULL,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NOT NULL;
+
+-- name: PurgeAuthors :execrows
 DELETE FROM authors
 WHERE
-  uuid = ?;
+  deleted_at < CAST(sqlc.arg(deleted_before) AS DATETIME(6));
+
+-- name: ListAuthorsPage :many
+SELECT
+  *
+FROM
+  authors
+WHERE
+  uuid > sqlc.arg(after_uuid)
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+  AND updated_at >= sqlc.arg(updated_from)
+  AND updated_at < sqlc.arg(updated_to)
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  ?;
+
+-- name: ListAuthorsPageIncludingDeleted :many
+SELECT
+  *
+FROM
+  authors
+WHERE
+  uuid > sqlc.arg(after_uuid)
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+  AND updated_at >= sqlc.arg(updated_from)
+  AND updated_at < sqlc.arg(updated_to)
+ORDER BY
+  uuid
+LIMIT
+  ?;
@@ -1,4 +1,15 @@
 -- name: GetBook :one
+SELECT
+  *
+FROM
+  books
+WHERE
+  uuid = ?
+  AND deleted_at IS NULL
+LIMIT
+  1;
+
+-- name: GetBookIncludingDeleted :one
 SELECT
   *
 FROM
@@ -13,6 +24,8 @@ SELECT
   *
 FROM
   books
+WHERE
+  deleted_at IS NULL
 ORDER BY
   uuid;
 
@@ -22,17 +35,46 @@ INSERT INTO
 VALUES
   (?, ?, ?);
 
--- name: UpdateBook :exec
+-- name: UpdateBook :execrows
 UPDATE books
 SET
-  title = ?
+  title = ?,
+  version = version + 1
 WHERE
-  uuid = ?;
+  uuid = ?
+  AND version = ?
+  AND deleted_at IS NULL;
 
--- name: DeleteBook :exec
+-- name: DeleteBook :execrows
+UPDATE books
+SET
+  deleted_at = CURRENT_TIMESTAMP(6),
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NULL;
+
+-- name: RestoreBook :execrows
+UPDATE books
+SET
+  deleted_at = NULL,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NOT NULL;
+
+-- name: PurgeBooks :execrows
 DELETE FROM books
 WHERE
-  uuid = ?;
+  deleted_at < CAST(sqlc.arg(deleted_before) AS DATETIME(6));
+
+-- name: ReassignBooks :execrows
+UPDATE books
+SET
+  publisher_uuid = sqlc.arg(to_publisher_uuid),
+  version = version + 1
+WHERE
+  publisher_uuid = sqlc.arg(from_publisher_uuid);
 
 -- name: GetBookPublisher :one
 SELECT
@@ -45,5 +87,55 @@ FROM
   INNER JOIN publishers AS p ON b.publisher_uuid = p.uuid
 WHERE
   b.uuid = ?
+  AND b.deleted_at IS NULL
+  AND p.deleted_at IS NULL
 LIMIT
   1;
+
+-- name: ListBooksPage :many
+SELECT
+  *
+FROM
+  books
+WHERE
+  uuid > sqlc.arg(after_uuid)
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+  AND updated_at >= sqlc.arg(updated_from)
+  AND updated_at < sqlc.arg(updated_to)
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  ?;
+
+-- name: ListBooksPageIncludingDeleted :many
+SELECT
+  *
+FROM
+  books
+WHERE
+  uuid > sqlc.arg(after_uuid)
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+  AND updated_at >= sqlc.arg(updated_from)
+  AND updated_at < sqlc.arg(updated_to)
+ORDER BY
+  uuid
+LIMIT
+  ?;
+
+-- name: ListBookSummariesPage :many
+SELECT
+  b.*,
+  p.name AS publisher_name
+FROM
+  books AS b
+  INNER JOIN publishers AS p ON b.publisher_uuid = p.uuid
+WHERE
+  b.uuid > sqlc.arg(after_uuid)
+  AND b.deleted_at IS NULL
+ORDER BY
+  b.uuid
+LIMIT
+  ?;
@@ -1,4 +1,15 @@
 -- name: GetPublisher :one
+SELECT
+  *
+FROM
+  publishers
+WHERE
+  uuid = ?
+  AND deleted_at IS NULL
+LIMIT
+  1;
+
+-- name: GetPublisherIncludingDeleted :one
 SELECT
   *
 FROM
@@ -13,6 +24,8 @@ SELECT
   *
 FROM
   publishers
+WHERE
+  deleted_at IS NULL
 ORDER BY
   uuid;
 
@@ -22,17 +35,55 @@ INSERT INTO
 VALUES
   (?, ?);
 
--- name: UpdatePublisher :exec
+-- name: UpdatePublisher :execrows
 UPDATE publishers
 SET
-  name = ?
+  name = ?,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND version = ?
+  AND deleted_at IS NULL;
+
+-- name: DeletePublisher :execrows
+UPDATE publishers
+SET
+  deleted_at = CURRENT_TIMESTAMP(6),
+  version = version + 1
 WHERE
-  uuid = ?;
+  uuid = ?
+  AND deleted_at IS NULL;
 
--- name: DeletePublisher :exec
+-- name: RestorePublisher :execrows
+UPDATE publishers
+SET
+  deleted_at = NULL,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NOT NULL;
+
+-- name: PurgePublishers :execrows
 DELETE FROM publishers
 WHERE
-  uuid = ?;
+  publishers.deleted_at < CAST(sqlc.arg(deleted_before) AS DATETIME(6))
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      books
+    WHERE
+      books.publisher_uuid = publishers.uuid
+  );
+
+-- name: CountBooksByPublisher :one
+SELECT
+  COUNT(*)
+FROM
+  books
+WHERE
+  publisher_uuid = ?
+  AND deleted_at IS NULL;
 
 -- name: GetPublisherBooks :many
 SELECT
@@ -45,6 +96,54 @@ FROM
   INNER JOIN books AS b ON p.uuid = b.publisher_uuid
 WHERE
   p.uuid = ?
+  AND p.deleted_at IS NULL
+  AND b.deleted_at IS NULL
 ORDER BY
   p.uuid,
   b.uuid;
+
+-- name: ListPublishersPage :many
+SELECT
+  *
+FROM
+  publishers
+WHERE
+  uuid > sqlc.arg(after_uuid)
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+  AND updated_at >= sqlc.arg(updated_from)
+  AND updated_at < sqlc.arg(updated_to)
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  ?;
+
+-- name: ListPublishersPageIncludingDeleted :many
+SELECT
+  *
+FROM
+  publishers
+WHERE
+  uuid > sqlc.arg(after_uuid)
+  AND created_at >= sqlc.arg(created_from)
+  AND created_at < sqlc.arg(created_to)
+  AND updated_at >= sqlc.arg(updated_from)
+  AND updated_at < sqlc.arg(updated_to)
+ORDER BY
+  uuid
+LIMIT
+  ?;
+
+-- name: ListPublishersByName :many
+SELECT
+  *
+FROM
+  publishers
+WHERE
+  name = ?
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  2;
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestDBTXInterceptors(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	errInjected := errors.New("injected")
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			failQuery string
+		}
+		expected struct {
+			names []string
+			err   error
+		}
+	}{
+		{
+			scenario: "create book",
+			expected: struct {
+				names []string
+				err   error
+			}{
+				names: []string{"CreatePublisher", "CreateAuthor", "CreateBook", "CreateAuthorBook", "GetBook", "GetPublisher", "GetAuthor"},
+			},
+		},
+		{
+			scenario: "fail link inside transaction",
+			input: struct {
+				failQuery string
+			}{
+				failQuery: "CreateAuthorBook",
+			},
+			expected: struct {
+				names []string
+				err   error
+			}{
+				names: []string{"CreatePublisher", "CreateAuthor", "CreateBook", "CreateAuthorBook"},
+				err:   errInjected,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				mu    sync.Mutex
+				names []string
+			)
+			record := dbtx.Observe(func(ctx context.Context, q dbtx.Query, _ dbtx.Result, _ time.Duration) {
+				mu.Lock()
+				defer mu.Unlock()
+				if q.Name != "" {
+					names = append(names, q.Name)
+				}
+			})
+			fail := func(ctx context.Context, q dbtx.Query, next dbtx.Handler) dbtx.Result {
+				if q.Name == tt.input.failQuery {
+					return dbtx.Result{Err: errInjected}
+				}
+				return next(ctx, q)
+			}
+
+			// create book
+			ctx := context.Background()
+			bookUuid := binuuid.New()
+			_, err := catalog.New(dbtx.Wrap(db, record, fail)).CreateBook(ctx, catalog.CreateBookParams{
+				Uuid:         bookUuid,
+				Title:        "book001",
+				NewPublisher: &sqlc.CreatePublisherParams{Uuid: binuuid.New(), Name: "publisher001"},
+				NewAuthors:   []sqlc.CreateAuthorParams{{Uuid: binuuid.New(), Name: "author001"}},
+			})
+			if !errors.Is(err, tt.expected.err) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.err)
+			}
+			if !reflect.DeepEqual(names, tt.expected.names) {
+				t.Errorf("got=%v, want=%v", names, tt.expected.names)
+			}
+
+			// the book exists unless the transaction was rolled back
+			_, err = sqlc.New(db).GetBook(ctx, bookUuid)
+			if tt.expected.err != nil && !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("got=%v, want=%v", err, sql.ErrNoRows)
+			}
+			if tt.expected.err == nil && err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/export"
+)
+
+func exportCatalog(ctx context.Context, db *dbtx.DB, args []string) (err error) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", string(export.FormatNDJSON), "output format: ndjson, json or csv")
+	output := fs.String("o", "-", "output `file`, - for stdout")
+	denormalize := fs.Bool("denormalize", false, "add publisher and author names to books and links")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("usage: export [flags] authors|publishers|books|author_books")
+	}
+	kind := export.Kind(fs.Arg(0))
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	bw := bufio.NewWriter(w)
+
+	// Read every page from one snapshot.
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	n, err := export.Export(ctx, catalog.New(tx), bw, kind, export.Options{
+		Format:      export.Format(*format),
+		Denormalize: *denormalize,
+	})
+	if err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	log.Printf("exported %d %s", n, kind)
+	return nil
+}
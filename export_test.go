@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/export"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestExportBooksDenormalized(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	authorUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+	bookUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+
+	type exportedBook struct {
+		Uuid          binuuid.UUID `json:"uuid"`
+		Title         string       `json:"title"`
+		PublisherName string       `json:"publisher_name"`
+		AuthorNames   []string     `json:"author_names"`
+	}
+
+	tests := []struct {
+		scenario string
+		input    export.Options
+		expected map[binuuid.UUID]exportedBook
+	}{
+		{
+			scenario: "one book per page",
+			input: export.Options{
+				Format:      export.FormatNDJSON,
+				Denormalize: true,
+				PageSize:    1,
+			},
+			expected: map[binuuid.UUID]exportedBook{
+				bookUuids[0]: {Uuid: bookUuids[0], Title: "book001", PublisherName: "publisher001", AuthorNames: []string{"author001", "author002"}},
+				bookUuids[1]: {Uuid: bookUuids[1], Title: "book002", PublisherName: "publisher001", AuthorNames: []string{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			queries := sqlc.New(tx)
+
+			// create publisher, books, authors and links
+			ctx := context.Background()
+			err = queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, bookUuid := range bookUuids {
+				err = queries.CreateBook(ctx, sqlc.CreateBookParams{Uuid: bookUuid, Title: []string{"book001", "book002"}[i], PublisherUuid: publisherUuid})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			for i, authorUuid := range authorUuids {
+				err = queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: authorUuid, Name: []string{"author002", "author001"}[i]})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, authorUuid := range authorUuids {
+				err = queries.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: authorUuid, BookUuid: bookUuids[0]})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// export
+			var b strings.Builder
+			_, err = export.Export(ctx, catalog.New(tx), &b, export.KindBooks, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := make(map[binuuid.UUID]exportedBook)
+			scanner := bufio.NewScanner(strings.NewReader(b.String()))
+			for scanner.Scan() {
+				var book exportedBook
+				if err := json.Unmarshal(scanner.Bytes(), &book); err != nil {
+					t.Fatal(err)
+				}
+				if _, ok := tt.expected[book.Uuid]; ok {
+					got[book.Uuid] = book
+				}
+			}
+
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got=%v, want=%v", got, tt.expected)
+			}
+		})
+	}
+}
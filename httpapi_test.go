@@ -0,0 +1,749 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/httpapi"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestHTTPAPI(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+	publisherUuid := binuuid.New()
+	bookUuid := binuuid.New()
+	newAuthorUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		// unsupported names a feature the scenario needs that
+		// go-mysql-server lacks, see testdb.Unsupported.
+		unsupported string
+		input       struct {
+			method string
+			path   string
+			body   string
+		}
+		expected struct {
+			status int
+			body   string
+		}
+	}{
+		{
+			scenario: "get author",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/authors/%s", authorUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusOK,
+				body:   fmt.Sprintf(`{"uuid":"%s","name":"author001","bio":"author001","version":1}`, authorUuid),
+			},
+		},
+		{
+			scenario: "get missing author",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/authors/%s", binuuid.New()),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusNotFound,
+				body:   `{"error":"not found"}`,
+			},
+		},
+		{
+			scenario: "get author with invalid uuid",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodGet,
+				path:   "/authors/author001",
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusBadRequest,
+			},
+		},
+		{
+			scenario: "create author",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPost,
+				path:   "/authors",
+				body:   fmt.Sprintf(`{"uuid":"%s","name":"author002","bio":null}`, newAuthorUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusCreated,
+				body:   fmt.Sprintf(`{"uuid":"%s","name":"author002","bio":null,"version":1}`, newAuthorUuid),
+			},
+		},
+		{
+			scenario: "create author with nil uuid",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPost,
+				path:   "/authors",
+				body:   fmt.Sprintf(`{"uuid":"%s","name":"author002"}`, binuuid.Nil),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusBadRequest,
+				body:   `{"error":"invalid uuid: the nil uuid is reserved"}`,
+			},
+		},
+		{
+			scenario: "update author without changes",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPut,
+				path:   fmt.Sprintf("/authors/%s", authorUuid),
+				body:   `{"name":"author001","bio":"author001","version":1}`,
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusOK,
+				body:   fmt.Sprintf(`{"uuid":"%s","name":"author001","bio":"author001","version":2}`, authorUuid),
+			},
+		},
+		{
+			scenario: "update author with stale version",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPut,
+				path:   fmt.Sprintf("/authors/%s", authorUuid),
+				body:   `{"name":"author001","bio":"author001","version":2}`,
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusConflict,
+				body:   `{"error":"version conflict"}`,
+			},
+		},
+		{
+			scenario: "update author without version",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPut,
+				path:   fmt.Sprintf("/authors/%s", authorUuid),
+				body:   `{"name":"author001","bio":"author001"}`,
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusPreconditionRequired,
+				body:   `{"error":"version or If-Match is required"}`,
+			},
+		},
+		{
+			scenario: "update missing book",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPut,
+				path:   fmt.Sprintf("/books/%s", binuuid.New()),
+				body:   `{"title":"book002","version":1}`,
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusNotFound,
+				body:   `{"error":"not found"}`,
+			},
+		},
+		{
+			scenario: "update book publisher",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPut,
+				path:   fmt.Sprintf("/books/%s", bookUuid),
+				body:   fmt.Sprintf(`{"title":"book002","publisher_uuid":"%s","version":1}`, publisherUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusBadRequest,
+				body:   `{"error":"invalid request body: json: unknown field \"publisher_uuid\""}`,
+			},
+		},
+		{
+			scenario: "get book publisher",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/books/%s/publisher", bookUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusOK,
+				body:   fmt.Sprintf(`{"book_uuid":"%s","book_title":"book001","publisher_uuid":"%s","publisher_name":"publisher001"}`, bookUuid, publisherUuid),
+			},
+		},
+		{
+			scenario: "get publisher books",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/publishers/%s/books", publisherUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusOK,
+				body:   fmt.Sprintf(`[{"publisher_uuid":"%s","publisher_name":"publisher001","book_uuid":"%s","book_title":"book001"}]`, publisherUuid, bookUuid),
+			},
+		},
+		{
+			scenario: "create duplicate author",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPost,
+				path:   "/authors",
+				body:   fmt.Sprintf(`{"uuid":"%s","name":"author001"}`, authorUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusConflict,
+				body:   `{"error":"already exists"}`,
+			},
+		},
+		{
+			scenario: "create book with missing publisher",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodPost,
+				path:   "/books",
+				body:   fmt.Sprintf(`{"title":"book002","publisher_uuid":"%s"}`, binuuid.New()),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusUnprocessableEntity,
+				body:   `{"error":"referenced row does not exist"}`,
+			},
+		},
+		{
+			scenario: "delete publisher with books",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodDelete,
+				path:   fmt.Sprintf("/publishers/%s", publisherUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusConflict,
+				body:   `{"error":"row is still referenced"}`,
+			},
+		},
+		{
+			scenario: "delete author with books",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodDelete,
+				path:   fmt.Sprintf("/authors/%s", authorUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusNoContent,
+			},
+		},
+		{
+			scenario: "delete author cascading to links",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodDelete,
+				path:   fmt.Sprintf("/authors/%s?cascade=true", authorUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusOK,
+				body:   `{"author_books":1}`,
+			},
+		},
+		{
+			scenario: "delete publisher reassigning to missing publisher",
+			// the catalog nests a savepoint in the test transaction
+			unsupported: "savepoints",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodDelete,
+				path:   fmt.Sprintf("/publishers/%s?reassign_to=%s", publisherUuid, binuuid.New()),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusUnprocessableEntity,
+				body:   `{"error":"referenced row does not exist"}`,
+			},
+		},
+		{
+			scenario: "delete author_book",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodDelete,
+				path:   fmt.Sprintf("/authors/%s/books/%s", authorUuid, bookUuid),
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusNoContent,
+			},
+		},
+		{
+			scenario: "debug endpoints are not served",
+			input: struct {
+				method string
+				path   string
+				body   string
+			}{
+				method: http.MethodGet,
+				path:   "/debug/db-stats",
+			},
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusNotFound,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			if tt.unsupported != "" {
+				testdb.Unsupported(t, tt.unsupported)
+			}
+			queries := sqlc.New(db)
+
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			queries = queries.WithTx(tx)
+
+			// create catalog
+			ctx := context.Background()
+			err = queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{
+				Uuid: authorUuid,
+				Name: "author001",
+				Bio:  sql.NullString{String: "author001", Valid: true},
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			err = queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{
+				Uuid: publisherUuid,
+				Name: "publisher001",
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			err = queries.CreateBook(ctx, sqlc.CreateBookParams{
+				Uuid:          bookUuid,
+				Title:         "book001",
+				PublisherUuid: publisherUuid,
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			err = queries.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{
+				AuthorUuid: authorUuid,
+				BookUuid:   bookUuid,
+			})
+			if err != nil {
+				t.Error(err)
+			}
+
+			// send request
+			req := httptest.NewRequest(tt.input.method, tt.input.path, strings.NewReader(tt.input.body))
+			rec := httptest.NewRecorder()
+			httpapi.New(tx).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected.status {
+				t.Errorf("got=%v, want=%v", rec.Code, tt.expected.status)
+			}
+			if tt.expected.body != "" {
+				body := timestampPattern.ReplaceAllString(strings.TrimSpace(rec.Body.String()), "")
+				if body != tt.expected.body {
+					t.Errorf("got=%v, want=%v", body, tt.expected.body)
+				}
+			}
+		})
+	}
+}
+
+// timestampPattern matches the timestamps set by the database, which cannot
+// be known in advance.
+var timestampPattern = regexp.MustCompile(`,"(created_at|updated_at)":"[^"]*"`)
+
+func TestHTTPAPIIfMatch(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	bookUuid := binuuid.New()
+	publisherUuid := binuuid.New()
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			ifMatch string
+		}
+		expected struct {
+			status int
+			etag   string
+		}
+	}{
+		{
+			scenario: "current version",
+			input: struct {
+				ifMatch string
+			}{
+				ifMatch: `"1"`,
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusOK,
+				etag:   `"2"`,
+			},
+		},
+		{
+			scenario: "stale version",
+			input: struct {
+				ifMatch string
+			}{
+				ifMatch: `"2"`,
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusPreconditionFailed,
+			},
+		},
+		{
+			scenario: "malformed version",
+			input: struct {
+				ifMatch string
+			}{
+				ifMatch: `"one"`,
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusBadRequest,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			queries := sqlc.New(db)
+
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			queries = queries.WithTx(tx)
+
+			// create book
+			ctx := context.Background()
+			err = queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{
+				Uuid: publisherUuid,
+				Name: "publisher001",
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			err = queries.CreateBook(ctx, sqlc.CreateBookParams{
+				Uuid:          bookUuid,
+				Title:         "book001",
+				PublisherUuid: publisherUuid,
+			})
+			if err != nil {
+				t.Error(err)
+			}
+
+			// send request
+			req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/books/%s", bookUuid), strings.NewReader(`{"title":"book002"}`))
+			req.Header.Set("If-Match", tt.input.ifMatch)
+			rec := httptest.NewRecorder()
+			httpapi.New(tx).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected.status {
+				t.Errorf("got=%v, want=%v", rec.Code, tt.expected.status)
+			}
+			if etag := rec.Header().Get("ETag"); etag != tt.expected.etag {
+				t.Errorf("got=%v, want=%v", etag, tt.expected.etag)
+			}
+		})
+	}
+}
+
+func TestHTTPAPIReassignETag(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	bookUuid := binuuid.New()
+	fromPublisherUuid := binuuid.New()
+	toPublisherUuid := binuuid.New()
+
+	// create book
+	queries := sqlc.New(db)
+	ctx := context.Background()
+	for _, publisherUuid := range []binuuid.UUID{fromPublisherUuid, toPublisherUuid} {
+		err := queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := queries.CreateBook(ctx, sqlc.CreateBookParams{Uuid: bookUuid, Title: "book001", PublisherUuid: fromPublisherUuid})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			method  string
+			path    string
+			body    string
+			ifMatch string
+		}
+		expected struct {
+			status int
+			etag   string
+		}
+	}{
+		{
+			scenario: "get book",
+			input: struct {
+				method  string
+				path    string
+				body    string
+				ifMatch string
+			}{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/books/%s", bookUuid),
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusOK,
+				etag:   `"1"`,
+			},
+		},
+		{
+			scenario: "delete publisher reassigning books",
+			input: struct {
+				method  string
+				path    string
+				body    string
+				ifMatch string
+			}{
+				method: http.MethodDelete,
+				path:   fmt.Sprintf("/publishers/%s?reassign_to=%s", fromPublisherUuid, toPublisherUuid),
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusOK,
+			},
+		},
+		{
+			scenario: "get reassigned book",
+			input: struct {
+				method  string
+				path    string
+				body    string
+				ifMatch string
+			}{
+				method: http.MethodGet,
+				path:   fmt.Sprintf("/books/%s", bookUuid),
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusOK,
+				etag:   `"2"`,
+			},
+		},
+		{
+			scenario: "update with etag from before reassignment",
+			input: struct {
+				method  string
+				path    string
+				body    string
+				ifMatch string
+			}{
+				method:  http.MethodPut,
+				path:    fmt.Sprintf("/books/%s", bookUuid),
+				body:    `{"title":"book002"}`,
+				ifMatch: `"1"`,
+			},
+			expected: struct {
+				status int
+				etag   string
+			}{
+				status: http.StatusPreconditionFailed,
+			},
+		},
+	}
+
+	// the scenarios build on each other
+	server := httpapi.New(db)
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(tt.input.method, tt.input.path, strings.NewReader(tt.input.body))
+			if tt.input.ifMatch != "" {
+				req.Header.Set("If-Match", tt.input.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expected.status {
+				t.Fatalf("got=%v, want=%v", rec.Code, tt.expected.status)
+			}
+			if etag := rec.Header().Get("ETag"); etag != tt.expected.etag {
+				t.Errorf("got=%v, want=%v", etag, tt.expected.etag)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/importer"
+)
+
+func importCatalog(ctx context.Context, db *dbtx.DB, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	publishers := fs.String("publishers", "", "publishers `file` (.csv or .json)")
+	authors := fs.String("authors", "", "authors `file` (.csv or .json)")
+	books := fs.String("books", "", "books `file` (.csv or .json)")
+	authorBooks := fs.String("author-books", "", "author-book links `file` (.csv or .json)")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without writing it")
+	partial := fs.Bool("partial", false, "write the valid rows even if others are rejected")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		inputs importer.Inputs
+		files  []*os.File
+	)
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+	for _, in := range []struct {
+		path  string
+		input **importer.Input
+	}{
+		{*publishers, &inputs.Publishers},
+		{*authors, &inputs.Authors},
+		{*books, &inputs.Books},
+		{*authorBooks, &inputs.AuthorBooks},
+	} {
+		if in.path == "" {
+			continue
+		}
+		format, err := importer.FormatOf(in.path)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(in.path)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		*in.input = &importer.Input{Name: in.path, Format: format, Reader: f}
+	}
+	if len(files) == 0 {
+		return errors.New("import: pass at least one of -publishers, -authors, -books and -author-books")
+	}
+
+	report, err := importer.Import(ctx, catalog.New(db), inputs, importer.Options{DryRun: *dryRun, Partial: *partial})
+	if err != nil {
+		return err
+	}
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+	if n := report.Rejected(); n > 0 && !*partial {
+		return fmt.Errorf("import: %d rows rejected, nothing was written; pass -partial to write the others", n)
+	}
+	return nil
+}
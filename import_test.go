@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/importer"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	existingPublisherUuid := binuuid.New()
+	publisherUuid := binuuid.New()
+	authorUuid := binuuid.New()
+	bookUuid := binuuid.New()
+
+	publishers := fmt.Sprintf("uuid,name\n%s,publisher001\n%s,import-publisher002\n", existingPublisherUuid, publisherUuid)
+	authors := fmt.Sprintf("uuid,name,bio\n%s,author001,\n,,no name\n", authorUuid)
+	books := fmt.Sprintf(`[
+  {"uuid": %q, "title": "book001", "publisher_name": "import-publisher002"},
+  {"title": "book002", "publisher_uuid": %q},
+  {"title": "book003", "publisher_name": "unknown"},
+  {"title": "book004", "publisher_uuid": %q}
+]`, bookUuid, existingPublisherUuid, binuuid.New())
+	authorBooks := fmt.Sprintf("author_uuid,book_uuid\n%s,%s\n%s,%s\n", authorUuid, bookUuid, authorUuid, binuuid.New())
+
+	tests := []struct {
+		scenario string
+		input    importer.Inputs
+		expected importer.Report
+	}{
+		{
+			scenario: "import all kinds",
+			input: importer.Inputs{
+				Publishers:  &importer.Input{Name: "publishers.csv", Format: importer.FormatCSV, Reader: strings.NewReader(publishers)},
+				Authors:     &importer.Input{Name: "authors.csv", Format: importer.FormatCSV, Reader: strings.NewReader(authors)},
+				Books:       &importer.Input{Name: "books.json", Format: importer.FormatJSON, Reader: strings.NewReader(books)},
+				AuthorBooks: &importer.Input{Name: "author_books.csv", Format: importer.FormatCSV, Reader: strings.NewReader(authorBooks)},
+			},
+			expected: importer.Report{
+				Files: []importer.FileReport{
+					{
+						Name:     "publishers.csv",
+						Inserted: 1,
+						Skipped:  []importer.Issue{{Line: 2, Reason: "already exists"}},
+					},
+					{
+						Name:     "authors.csv",
+						Inserted: 1,
+						Rejected: []importer.Issue{{Line: 3, Reason: "name is required"}},
+					},
+					{
+						Name:     "books.json",
+						Inserted: 2,
+						Rejected: []importer.Issue{
+							{Line: 4, Reason: `publisher "unknown" does not exist`},
+							{Line: 5, Reason: "referenced row does not exist"},
+						},
+					},
+					{
+						Name:     "author_books.csv",
+						Inserted: 1,
+						Rejected: []importer.Issue{{Line: 3, Reason: "referenced row does not exist"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// test with transaction
+			tx, err := db.Begin()
+			if err != nil {
+				t.Error(err)
+			}
+			t.Cleanup(func() {
+				err = tx.Rollback()
+				if err != nil {
+					t.Error(err)
+				}
+			})
+
+			service := catalog.New(tx)
+
+			// create publisher
+			ctx := context.Background()
+			err = service.Queries().CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: existingPublisherUuid, Name: "publisher001"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// import
+			report, err := importer.Import(ctx, service, tt.input, importer.Options{Partial: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(report, tt.expected) {
+				t.Errorf("got=%v, want=%v", report, tt.expected)
+			}
+
+			// book imported by publisher name
+			book, err := service.Queries().GetBook(ctx, bookUuid)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if book.PublisherUuid != publisherUuid {
+				t.Errorf("got=%v, want=%v", book.PublisherUuid, publisherUuid)
+			}
+		})
+	}
+}
+
+func TestImportRollsBackRejected(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "savepoints")
+	db := testdb.New(t)
+
+	authorUuid := binuuid.New()
+	authors := fmt.Sprintf("uuid,name,bio\n%s,author001,\n,,no name\n", authorUuid)
+
+	// test with transaction
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		err = tx.Rollback()
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	service := catalog.New(tx)
+
+	// import
+	ctx := context.Background()
+	report, err := importer.Import(ctx, service, importer.Inputs{
+		Authors: &importer.Input{Name: "authors.csv", Format: importer.FormatCSV, Reader: strings.NewReader(authors)},
+	}, importer.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := importer.Report{
+		Files: []importer.FileReport{
+			{
+				Name:     "authors.csv",
+				Inserted: 1,
+				Rejected: []importer.Issue{{Line: 3, Reason: "name is required"}},
+			},
+		},
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Errorf("got=%v, want=%v", report, expected)
+	}
+
+	// the valid author was rolled back with the rejected one
+	_, err = service.Queries().GetAuthor(ctx, authorUuid)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("got=%v, want=%v", err, sql.ErrNoRows)
+	}
+}
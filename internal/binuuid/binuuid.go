@@ -0,0 +1,69 @@
+// Package binuuid provides a UUID that is stored in MySQL as BINARY(16).
+//
+// UUID has the same representation as github.com/google/uuid.UUID and
+// converts to and from it freely, but implements driver.Valuer by writing
+// the 16 raw bytes instead of the 36-character text form. Scan accepts
+// both forms.
+package binuuid
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+type UUID uuid.UUID
+
+// Nil is the zero UUID. It sorts before every other UUID.
+var Nil UUID
+
+// ErrNil reports a row uuid that is Nil. Keyset pagination starts its first
+// page after Nil, so a row with it would never be listed.
+var ErrNil = errors.New("the nil uuid is reserved")
+
+func New() UUID {
+	return UUID(uuid.New())
+}
+
+func Parse(s string) (UUID, error) {
+	u, err := uuid.Parse(s)
+	return UUID(u), err
+}
+
+func MustParse(s string) UUID {
+	return UUID(uuid.MustParse(s))
+}
+
+// FromBytes returns the UUID stored in the 16 bytes b.
+func FromBytes(b []byte) (UUID, error) {
+	u, err := uuid.FromBytes(b)
+	return UUID(u), err
+}
+
+// UUID returns u as a github.com/google/uuid.UUID.
+func (u UUID) UUID() uuid.UUID {
+	return uuid.UUID(u)
+}
+
+func (u UUID) String() string {
+	return uuid.UUID(u).String()
+}
+
+// Value implements driver.Valuer.
+func (u UUID) Value() (driver.Value, error) {
+	return u[:], nil
+}
+
+// Scan implements sql.Scanner.
+func (u *UUID) Scan(src any) error {
+	return (*uuid.UUID)(u).Scan(src)
+}
+
+func (u UUID) MarshalText() ([]byte, error) {
+	return uuid.UUID(u).MarshalText()
+}
+
+func (u *UUID) UnmarshalText(data []byte) error {
+	return (*uuid.UUID)(u).UnmarshalText(data)
+}
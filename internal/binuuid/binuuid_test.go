@@ -0,0 +1,104 @@
+package binuuid
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    UUID
+		expected []byte
+	}{
+		{
+			scenario: "raw bytes",
+			input:    MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+			expected: []byte{0x01, 0x91, 0xd6, 0xc4, 0x7f, 0x6e, 0x7c, 0x1a, 0x9a, 0x53, 0x2f, 0x1d, 0x7e, 0x4f, 0x9b, 0x10},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			v, err := tt.input.Value()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			b, ok := v.([]byte)
+			if !ok || !bytes.Equal(b, tt.expected) {
+				t.Errorf("got=%v, want=%v", v, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    any
+		expected UUID
+	}{
+		{
+			scenario: "binary form",
+			input:    []byte{0x01, 0x91, 0xd6, 0xc4, 0x7f, 0x6e, 0x7c, 0x1a, 0x9a, 0x53, 0x2f, 0x1d, 0x7e, 0x4f, 0x9b, 0x10},
+			expected: MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+		},
+		{
+			scenario: "text form",
+			input:    []byte("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+			expected: MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			var u UUID
+			if err := u.Scan(tt.input); err != nil {
+				t.Fatal(err)
+			}
+
+			if u != tt.expected {
+				t.Errorf("got=%v, want=%v", u, tt.expected)
+			}
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    UUID
+		expected string
+	}{
+		{
+			scenario: "text form",
+			input:    MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+			expected: `"0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			b, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != tt.expected {
+				t.Errorf("got=%v, want=%v", string(b), tt.expected)
+			}
+
+			var u UUID
+			if err := json.Unmarshal(b, &u); err != nil {
+				t.Fatal(err)
+			}
+			if u != tt.input {
+				t.Errorf("got=%v, want=%v", u, tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type CreateBookParams struct {
+	Uuid  binuuid.UUID
+	Title string
+	// PublisherUuid references an existing publisher. It is ignored when
+	// NewPublisher is set.
+	PublisherUuid binuuid.UUID
+	// NewPublisher is created in the same transaction as the book.
+	NewPublisher *sqlc.CreatePublisherParams
+	// AuthorUuids reference existing authors to link to the book.
+	AuthorUuids []binuuid.UUID
+	// NewAuthors are created in the same transaction and linked to the book.
+	NewAuthors []sqlc.CreateAuthorParams
+}
+
+type BookDetail struct {
+	Book      sqlc.Book
+	Publisher sqlc.Publisher
+	Authors   []sqlc.Author
+}
+
+// CreateBook creates a book together with its publisher, authors and
+// author_books links. Nothing is written unless every step succeeds.
+func (s *Service) CreateBook(ctx context.Context, arg CreateBookParams) (BookDetail, error) {
+	var detail BookDetail
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		publisherUuid := arg.PublisherUuid
+		if arg.NewPublisher != nil {
+			if err := q.CreatePublisher(ctx, *arg.NewPublisher); err != nil {
+				return fmt.Errorf("create publisher %s: %w", arg.NewPublisher.Uuid, err)
+			}
+			publisherUuid = arg.NewPublisher.Uuid
+		}
+
+		authorUuids := make([]binuuid.UUID, 0, len(arg.AuthorUuids)+len(arg.NewAuthors))
+		authorUuids = append(authorUuids, arg.AuthorUuids...)
+		for _, params := range arg.NewAuthors {
+			if err := q.CreateAuthor(ctx, params); err != nil {
+				return fmt.Errorf("create author %s: %w", params.Uuid, err)
+			}
+			authorUuids = append(authorUuids, params.Uuid)
+		}
+
+		err := q.CreateBook(ctx, sqlc.CreateBookParams{
+			Uuid:          arg.Uuid,
+			Title:         arg.Title,
+			PublisherUuid: publisherUuid,
+		})
+		if err != nil {
+			return fmt.Errorf("create book %s: %w", arg.Uuid, err)
+		}
+
+		for _, authorUuid := range authorUuids {
+			err := q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{
+				AuthorUuid: authorUuid,
+				BookUuid:   arg.Uuid,
+			})
+			if err != nil {
+				return fmt.Errorf("link author %s to book %s: %w", authorUuid, arg.Uuid, err)
+			}
+		}
+
+		detail.Book, err = q.GetBook(ctx, arg.Uuid)
+		if err != nil {
+			return fmt.Errorf("get book %s: %w", arg.Uuid, err)
+		}
+		detail.Publisher, err = q.GetPublisher(ctx, publisherUuid)
+		if err != nil {
+			return fmt.Errorf("get publisher %s: %w", publisherUuid, err)
+		}
+		detail.Authors = make([]sqlc.Author, 0, len(authorUuids))
+		for _, authorUuid := range authorUuids {
+			author, err := q.GetAuthor(ctx, authorUuid)
+			if err != nil {
+				return fmt.Errorf("get author %s: %w", authorUuid, err)
+			}
+			detail.Authors = append(detail.Authors, author)
+		}
+		return nil
+	})
+	if err != nil {
+		return BookDetail{}, err
+	}
+
+	return detail, nil
+}
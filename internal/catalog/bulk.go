@@ -0,0 +1,246 @@
+package catalog
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+const (
+	// maxPlaceholders is the number of placeholders MySQL accepts in one
+	// prepared statement.
+	maxPlaceholders = 65535
+	// defaultMaxAllowedPacket is used when the server setting cannot be
+	// read; it is the MySQL 8 default.
+	defaultMaxAllowedPacket = 64 << 20
+	// argOverhead approximates the per-value bytes a statement carries on
+	// top of the value itself: type, length prefix and placeholder.
+	argOverhead = 16
+)
+
+// BulkResult reports the outcome of a bulk insert.
+type BulkResult struct {
+	Inserted int
+	// Failures lists the rows that were rejected, in input order. The other
+	// rows are inserted regardless.
+	Failures []BulkFailure
+}
+
+// BulkFailure is a rejected row of a bulk insert.
+type BulkFailure struct {
+	// Index is the position of the row in the input slice.
+	Index int
+	// Err is translated with dberr.Translate, e.g. dberr.ErrAlreadyExists
+	// for a duplicate uuid.
+	Err error
+}
+
+func (f BulkFailure) Error() string {
+	return fmt.Sprintf("row %d: %s", f.Index, f.Err)
+}
+
+func (f BulkFailure) Unwrap() error {
+	return f.Err
+}
+
+// bulkTable describes the multi-row INSERT of one table.
+type bulkTable[T any] struct {
+	name    string
+	columns []string
+	args    func(T) []any
+}
+
+var (
+	authorsTable = bulkTable[sqlc.CreateAuthorParams]{
+		name:    "authors",
+		columns: []string{"uuid", "name", "bio"},
+		args: func(p sqlc.CreateAuthorParams) []any {
+			return []any{p.Uuid, p.Name, p.Bio}
+		},
+	}
+	publishersTable = bulkTable[sqlc.CreatePublisherParams]{
+		name:    "publishers",
+		columns: []string{"uuid", "name"},
+		args: func(p sqlc.CreatePublisherParams) []any {
+			return []any{p.Uuid, p.Name}
+		},
+	}
+	booksTable = bulkTable[sqlc.CreateBookParams]{
+		name:    "books",
+		columns: []string{"uuid", "title", "publisher_uuid"},
+		args: func(p sqlc.CreateBookParams) []any {
+			return []any{p.Uuid, p.Title, p.PublisherUuid}
+		},
+	}
+	authorBooksTable = bulkTable[sqlc.CreateAuthorBookParams]{
+		name:    "author_books",
+		columns: []string{"author_uuid", "book_uuid"},
+		args: func(p sqlc.CreateAuthorBookParams) []any {
+			return []any{p.AuthorUuid, p.BookUuid}
+		},
+	}
+)
+
+// BulkCreateAuthors inserts authors with multi-row INSERT statements in one
+// transaction. Rows rejected by the database, e.g. duplicates, are reported
+// in the result and do not prevent the other rows from being inserted.
+// Any other error rolls back the whole batch.
+func (s *Service) BulkCreateAuthors(ctx context.Context, rows []sqlc.CreateAuthorParams) (BulkResult, error) {
+	return bulkCreate(ctx, s, authorsTable, rows)
+}
+
+// BulkCreatePublishers is BulkCreateAuthors for publishers.
+func (s *Service) BulkCreatePublishers(ctx context.Context, rows []sqlc.CreatePublisherParams) (BulkResult, error) {
+	return bulkCreate(ctx, s, publishersTable, rows)
+}
+
+// BulkCreateBooks is BulkCreateAuthors for books. Books whose publisher does
+// not exist are reported with dberr.ErrReferenceMissing.
+func (s *Service) BulkCreateBooks(ctx context.Context, rows []sqlc.CreateBookParams) (BulkResult, error) {
+	return bulkCreate(ctx, s, booksTable, rows)
+}
+
+// BulkCreateAuthorBooks is BulkCreateAuthors for author_books links.
+func (s *Service) BulkCreateAuthorBooks(ctx context.Context, rows []sqlc.CreateAuthorBookParams) (BulkResult, error) {
+	return bulkCreate(ctx, s, authorBooksTable, rows)
+}
+
+func bulkCreate[T any](ctx context.Context, s *Service, table bulkTable[T], rows []T) (BulkResult, error) {
+	var result BulkResult
+
+	err := s.inTx(ctx, func(db sqlc.DBTX) error {
+		// Leave half of the packet as headroom for escaping when the
+		// driver interpolates parameters into the statement text.
+		budget := maxAllowedPacket(ctx, db) / 2
+
+		for start := 0; start < len(rows); {
+			end := chunkEnd(table, rows, start, budget)
+			inserted, failures, err := insertChunk(ctx, db, table, rows[start:end], start)
+			if err != nil {
+				return err
+			}
+			result.Inserted += inserted
+			result.Failures = append(result.Failures, failures...)
+			start = end
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkResult{}, dberr.Translate(err)
+	}
+	return result, nil
+}
+
+// chunkEnd returns the end of the chunk starting at start whose estimated
+// statement size stays within budget. A chunk holds at least one row.
+func chunkEnd[T any](table bulkTable[T], rows []T, start int, budget int) int {
+	size := len(insertPrefix(table))
+	placeholders := 0
+	end := start
+	for end < len(rows) {
+		args := table.args(rows[end])
+		rowSize := 0
+		for _, arg := range args {
+			rowSize += argSize(arg) + argOverhead
+		}
+		if end > start && (size+rowSize > budget || placeholders+len(args) > maxPlaceholders) {
+			break
+		}
+		size += rowSize
+		placeholders += len(args)
+		end++
+	}
+	return end
+}
+
+// insertChunk inserts rows with one statement. When the database rejects
+// the statement because of a row, the chunk is bisected until only the
+// offending rows fail, which takes a few statements per bad row rather than
+// one per row. InnoDB rolls back a failed statement as a whole, so no
+// savepoint is needed.
+func insertChunk[T any](ctx context.Context, db sqlc.DBTX, table bulkTable[T], rows []T, offset int) (int, []BulkFailure, error) {
+	err := execInsert(ctx, db, table, rows)
+	switch {
+	case err == nil:
+		return len(rows), nil, nil
+	case !isRowError(err):
+		return 0, nil, fmt.Errorf("insert %s: %w", table.name, err)
+	case len(rows) == 1:
+		return 0, []BulkFailure{{Index: offset, Err: dberr.Translate(err)}}, nil
+	}
+
+	// Insert the first half first, so that of two duplicates in the chunk
+	// the earlier one wins, as it would row by row.
+	mid := len(rows) / 2
+	inserted, failures, err := insertChunk(ctx, db, table, rows[:mid], offset)
+	if err != nil {
+		return 0, nil, err
+	}
+	n, more, err := insertChunk(ctx, db, table, rows[mid:], offset+mid)
+	if err != nil {
+		return 0, nil, err
+	}
+	return inserted + n, append(failures, more...), nil
+}
+
+func execInsert[T any](ctx context.Context, db sqlc.DBTX, table bulkTable[T], rows []T) error {
+	var b strings.Builder
+	b.WriteString(insertPrefix(table))
+
+	group := "(" + strings.Repeat("?, ", len(table.columns)-1) + "?)"
+	args := make([]any, 0, len(rows)*len(table.columns))
+	for i, row := range rows {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(group)
+		args = append(args, table.args(row)...)
+	}
+
+	_, err := db.ExecContext(ctx, b.String(), args...)
+	return err
+}
+
+func insertPrefix[T any](table bulkTable[T]) string {
+	return "INSERT INTO " + table.name + " (" + strings.Join(table.columns, ", ") + ") VALUES "
+}
+
+// isRowError reports whether err was caused by the data of a row rather
+// than by the connection or the statement.
+func isRowError(err error) bool {
+	err = dberr.Translate(err)
+	return errors.Is(err, dberr.ErrAlreadyExists) || errors.Is(err, dberr.ErrReferenceMissing)
+}
+
+// maxAllowedPacket reads the max_allowed_packet of the session.
+func maxAllowedPacket(ctx context.Context, db sqlc.DBTX) int {
+	var size int
+	if err := db.QueryRowContext(ctx, "SELECT @@max_allowed_packet").Scan(&size); err != nil || size <= 0 {
+		return defaultMaxAllowedPacket
+	}
+	return size
+}
+
+// argSize estimates the encoded size of a statement argument.
+func argSize(arg any) int {
+	switch v := arg.(type) {
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	case time.Time:
+		return 12
+	}
+	if valuer, ok := arg.(driver.Valuer); ok {
+		if v, err := valuer.Value(); err == nil && v != nil {
+			return argSize(v)
+		}
+	}
+	return 8
+}
@@ -0,0 +1,181 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestChunkEnd(t *testing.T) {
+	rows := make([]sqlc.CreatePublisherParams, 10)
+	for i := range rows {
+		rows[i] = sqlc.CreatePublisherParams{Uuid: binuuid.New(), Name: strings.Repeat("x", 100)}
+	}
+	// uuid and name with their overhead
+	rowSize := 16 + argOverhead + 100 + argOverhead
+	prefix := len(insertPrefix(publishersTable))
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			start  int
+			budget int
+		}
+		expected int
+	}{
+		{
+			scenario: "everything fits",
+			input: struct {
+				start  int
+				budget int
+			}{
+				start:  0,
+				budget: 1 << 20,
+			},
+			expected: 10,
+		},
+		{
+			scenario: "three rows fit",
+			input: struct {
+				start  int
+				budget int
+			}{
+				start:  2,
+				budget: prefix + 3*rowSize,
+			},
+			expected: 5,
+		},
+		{
+			scenario: "oversized row",
+			input: struct {
+				start  int
+				budget int
+			}{
+				start:  4,
+				budget: 1,
+			},
+			expected: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			end := chunkEnd(publishersTable, rows, tt.input.start, tt.input.budget)
+
+			if end != tt.expected {
+				t.Errorf("got=%v, want=%v", end, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChunkEndPlaceholders(t *testing.T) {
+	rows := make([]sqlc.CreateAuthorBookParams, maxPlaceholders)
+
+	end := chunkEnd(authorBooksTable, rows, 0, 1<<30)
+
+	if want := maxPlaceholders / 2; end != want {
+		t.Errorf("got=%v, want=%v", end, want)
+	}
+}
+
+// rejectingDB fails every INSERT that carries one of the rejected names with
+// a duplicate entry error, and counts the statements.
+type rejectingDB struct {
+	sqlc.DBTX
+	rejected   map[string]bool
+	statements int
+}
+
+func (db *rejectingDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	db.statements++
+	for _, arg := range args {
+		if name, ok := arg.(string); ok && db.rejected[name] {
+			return nil, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+		}
+	}
+	return nil, nil
+}
+
+func TestInsertChunk(t *testing.T) {
+	rows := make([]sqlc.CreatePublisherParams, 1000)
+	for i := range rows {
+		rows[i] = sqlc.CreatePublisherParams{Uuid: binuuid.New(), Name: "publisher"}
+	}
+	rows[10].Name = "rejected001"
+	rows[700].Name = "rejected002"
+
+	tests := []struct {
+		scenario string
+		input    map[string]bool
+		expected struct {
+			inserted   int
+			failures   []int
+			statements int
+		}
+	}{
+		{
+			scenario: "no rejected rows",
+			expected: struct {
+				inserted   int
+				failures   []int
+				statements int
+			}{
+				inserted:   1000,
+				statements: 1,
+			},
+		},
+		{
+			scenario: "two rejected rows",
+			input:    map[string]bool{"rejected001": true, "rejected002": true},
+			expected: struct {
+				inserted   int
+				failures   []int
+				statements int
+			}{
+				inserted: 998,
+				failures: []int{110, 800},
+				// rather than 1001 when retrying row by row
+				statements: 39,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := &rejectingDB{rejected: tt.input}
+
+			inserted, failures, err := insertChunk(context.Background(), db, publishersTable, rows, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if inserted != tt.expected.inserted {
+				t.Errorf("got=%v, want=%v", inserted, tt.expected.inserted)
+			}
+			var indexes []int
+			for _, f := range failures {
+				if !errors.Is(f, dberr.ErrAlreadyExists) {
+					t.Errorf("got=%v, want=%v", f.Err, dberr.ErrAlreadyExists)
+				}
+				indexes = append(indexes, f.Index)
+			}
+			if !reflect.DeepEqual(indexes, tt.expected.failures) {
+				t.Errorf("got=%v, want=%v", indexes, tt.expected.failures)
+			}
+			if db.statements != tt.expected.statements {
+				t.Errorf("got=%v, want=%v", db.statements, tt.expected.statements)
+			}
+		})
+	}
+}
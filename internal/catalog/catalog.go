@@ -0,0 +1,132 @@
+// Package catalog provides business operations on top of sqlc.Queries.
+// Every operation that touches more than one row runs in a single
+// transaction and either commits or rolls back as a unit. Errors returned
+// by the service are translated with dberr.Translate.
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type Service struct {
+	db      sqlc.DBTX
+	queries *sqlc.Queries
+}
+
+// New returns a service running against db. When db is a *sql.DB or a
+// *dbtx.DB every operation opens its own transaction; when it is a *sql.Tx
+// or a *dbtx.Tx operations join that transaction and are made atomic with a
+// savepoint.
+func New(db sqlc.DBTX) *Service {
+	return &Service{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// Queries returns the non-transactional queries bound to the service's database.
+func (s *Service) Queries() *sqlc.Queries {
+	return s.queries
+}
+
+// InTx runs fn inside a transaction. The transaction is committed when fn
+// returns nil and rolled back otherwise. Errors are translated with
+// dberr.Translate.
+func (s *Service) InTx(ctx context.Context, fn func(q *sqlc.Queries) error) error {
+	return dberr.Translate(s.inTx(ctx, func(db sqlc.DBTX) error {
+		return fn(sqlc.New(db))
+	}))
+}
+
+// Atomic runs fn with a service bound to a single transaction, so that
+// several service operations commit or roll back together. Operations
+// called by fn are nested with savepoints.
+func (s *Service) Atomic(ctx context.Context, fn func(s *Service) error) error {
+	return dberr.Translate(s.inTx(ctx, func(db sqlc.DBTX) error {
+		return fn(New(db))
+	}))
+}
+
+// inTx is InTx for operations that need the transaction itself, e.g. to
+// run statements that sqlc cannot generate.
+func (s *Service) inTx(ctx context.Context, fn func(db sqlc.DBTX) error) (err error) {
+	var tx interface {
+		sqlc.DBTX
+		Commit() error
+		Rollback() error
+	}
+	switch db := s.db.(type) {
+	case interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	}:
+		tx, err = db.BeginTx(ctx, nil)
+	case *dbtx.DB:
+		// Keep the interceptors in the transaction.
+		tx, err = db.BeginTx(ctx, nil)
+	default:
+		return s.inSavepoint(ctx, fn)
+	}
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		// Release the connection before a panic of fn goes on.
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err == nil {
+			return
+		}
+		if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+			err = errors.Join(err, fmt.Errorf("rollback transaction: %w", rbErr))
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// savepointSeq numbers savepoints. MySQL replaces a savepoint that reuses
+// a name, so nested operations need distinct ones.
+var savepointSeq atomic.Uint64
+
+// inSavepoint runs fn inside the caller's transaction and undoes its writes
+// when fn fails.
+func (s *Service) inSavepoint(ctx context.Context, fn func(db sqlc.DBTX) error) (err error) {
+	savepoint := fmt.Sprintf("catalog_savepoint_%d", savepointSeq.Add(1))
+	if _, err = s.db.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+	defer func() {
+		if err == nil {
+			return
+		}
+		if _, rbErr := s.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint); rbErr != nil {
+			err = errors.Join(err, fmt.Errorf("rollback to savepoint: %w", rbErr))
+		}
+	}()
+
+	if err = fn(s.db); err != nil {
+		return err
+	}
+
+	if _, err = s.db.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// recordingDB records the statements it is asked to run.
+type recordingDB struct {
+	sqlc.DBTX
+	statements []string
+}
+
+func (db *recordingDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	db.statements = append(db.statements, query)
+	return nil, nil
+}
+
+func TestInSavepoint(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		scenario string
+		input    func(db sqlc.DBTX) error
+		expected struct {
+			err        error
+			statements []string
+		}
+	}{
+		{
+			scenario: "released",
+			input: func(db sqlc.DBTX) error {
+				return nil
+			},
+			expected: struct {
+				err        error
+				statements []string
+			}{
+				statements: []string{
+					"SAVEPOINT 1",
+					"RELEASE SAVEPOINT 1",
+				},
+			},
+		},
+		{
+			scenario: "rolled back",
+			input: func(db sqlc.DBTX) error {
+				return errFailed
+			},
+			expected: struct {
+				err        error
+				statements []string
+			}{
+				err: errFailed,
+				statements: []string{
+					"SAVEPOINT 1",
+					"ROLLBACK TO SAVEPOINT 1",
+				},
+			},
+		},
+		{
+			scenario: "nested",
+			input: func(db sqlc.DBTX) error {
+				// the outer operation goes on after the nested one failed
+				err := New(db).inTx(context.Background(), func(db sqlc.DBTX) error {
+					return errFailed
+				})
+				if !errors.Is(err, errFailed) {
+					return fmt.Errorf("got=%v, want=%v", err, errFailed)
+				}
+				return nil
+			},
+			expected: struct {
+				err        error
+				statements []string
+			}{
+				statements: []string{
+					"SAVEPOINT 1",
+					"SAVEPOINT 2",
+					"ROLLBACK TO SAVEPOINT 2",
+					"RELEASE SAVEPOINT 1",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			db := &recordingDB{}
+			err := New(db).inTx(context.Background(), tt.input)
+			if !errors.Is(err, tt.expected.err) {
+				t.Errorf("got=%v, want=%v", err, tt.expected.err)
+			}
+
+			// savepoint names are global, number them in order of appearance
+			names := map[string]int{}
+			got := make([]string, len(db.statements))
+			for i, statement := range db.statements {
+				verb, name, _ := strings.Cut(statement, " catalog_savepoint_")
+				if _, ok := names[name]; !ok {
+					names[name] = len(names) + 1
+				}
+				got[i] = fmt.Sprintf("%s %d", verb, names[name])
+			}
+			if !reflect.DeepEqual(got, tt.expected.statements) {
+				t.Errorf("got=%v, want=%v", got, tt.expected.statements)
+			}
+		})
+	}
+}
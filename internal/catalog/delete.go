@@ -0,0 +1,150 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// DeleteAuthor soft-deletes an author. Its author_books links are kept but
+// hidden until the author is restored. It fails with dberr.ErrNotFound when
+// there is no live author.
+func (s *Service) DeleteAuthor(ctx context.Context, uuid binuuid.UUID) error {
+	return dberr.CheckAffected(s.queries.DeleteAuthor(ctx, uuid))
+}
+
+// DeleteBook soft-deletes a book. Its author_books links are kept but
+// hidden until the book is restored.
+func (s *Service) DeleteBook(ctx context.Context, uuid binuuid.UUID) error {
+	return dberr.CheckAffected(s.queries.DeleteBook(ctx, uuid))
+}
+
+// DeletePublisher soft-deletes a publisher. It fails with dberr.ErrInUse
+// while live books reference the publisher.
+func (s *Service) DeletePublisher(ctx context.Context, uuid binuuid.UUID) error {
+	return s.InTx(ctx, func(q *sqlc.Queries) error {
+		books, err := q.CountBooksByPublisher(ctx, uuid)
+		if err != nil {
+			return fmt.Errorf("count books of publisher %s: %w", uuid, err)
+		}
+		if books > 0 {
+			return fmt.Errorf("publisher %s has %d books: %w", uuid, books, dberr.ErrInUse)
+		}
+		return dberr.CheckAffected(q.DeletePublisher(ctx, uuid))
+	})
+}
+
+// DeleteAuthorResult summarizes DeleteAuthorCascade.
+type DeleteAuthorResult struct {
+	// AuthorBooks is the number of links removed with the author.
+	AuthorBooks int64
+}
+
+// DeleteAuthorCascade removes the author's author_books links and
+// soft-deletes the author in one transaction. The links are deleted for
+// good: restoring the author does not bring them back.
+func (s *Service) DeleteAuthorCascade(ctx context.Context, uuid binuuid.UUID) (DeleteAuthorResult, error) {
+	var result DeleteAuthorResult
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		if result.AuthorBooks, err = q.DeleteAuthorBooksByAuthor(ctx, uuid); err != nil {
+			return fmt.Errorf("delete books of author %s: %w", uuid, err)
+		}
+		return dberr.CheckAffected(q.DeleteAuthor(ctx, uuid))
+	})
+	if err != nil {
+		return DeleteAuthorResult{}, err
+	}
+	return result, nil
+}
+
+// DeletePublisherResult summarizes DeletePublisherReassign.
+type DeletePublisherResult struct {
+	// Books is the number of books moved to the other publisher, including
+	// soft-deleted ones.
+	Books int64
+}
+
+// DeletePublisherReassign moves every book of a publisher to the publisher
+// to and soft-deletes the former in one transaction. It fails with
+// dberr.ErrReferenceMissing when to is not a live publisher.
+func (s *Service) DeletePublisherReassign(ctx context.Context, uuid binuuid.UUID, to binuuid.UUID) (DeletePublisherResult, error) {
+	if uuid == to {
+		return DeletePublisherResult{}, fmt.Errorf("reassign books of publisher %s to itself: %w", uuid, dberr.ErrInUse)
+	}
+
+	var result DeletePublisherResult
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		if _, err := q.GetPublisher(ctx, to); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("publisher %s: %w", to, dberr.ErrReferenceMissing)
+			}
+			return fmt.Errorf("get publisher %s: %w", to, err)
+		}
+
+		var err error
+		result.Books, err = q.ReassignBooks(ctx, sqlc.ReassignBooksParams{
+			ToPublisherUuid:   to,
+			FromPublisherUuid: uuid,
+		})
+		if err != nil {
+			return fmt.Errorf("reassign books of publisher %s: %w", uuid, err)
+		}
+		return dberr.CheckAffected(q.DeletePublisher(ctx, uuid))
+	})
+	if err != nil {
+		return DeletePublisherResult{}, err
+	}
+	return result, nil
+}
+
+// PurgeResult counts the rows removed by Purge.
+type PurgeResult struct {
+	AuthorBooks int64
+	Books       int64
+	Authors     int64
+	Publishers  int64
+}
+
+// Purge permanently removes rows that were soft-deleted before
+// deletedBefore, together with the author_books links that reference them.
+// Publishers that are still referenced by a book, deleted or not, are kept
+// until that book is purged as well.
+func (s *Service) Purge(ctx context.Context, deletedBefore time.Time) (PurgeResult, error) {
+	var result PurgeResult
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		authorLinks, err := q.PurgeAuthorBooksOfAuthors(ctx, deletedBefore)
+		if err != nil {
+			return fmt.Errorf("purge author_books of authors: %w", err)
+		}
+		bookLinks, err := q.PurgeAuthorBooksOfBooks(ctx, deletedBefore)
+		if err != nil {
+			return fmt.Errorf("purge author_books of books: %w", err)
+		}
+		result.AuthorBooks = authorLinks + bookLinks
+
+		if result.Books, err = q.PurgeBooks(ctx, deletedBefore); err != nil {
+			return fmt.Errorf("purge books: %w", err)
+		}
+		if result.Authors, err = q.PurgeAuthors(ctx, deletedBefore); err != nil {
+			return fmt.Errorf("purge authors: %w", err)
+		}
+		if result.Publishers, err = q.PurgePublishers(ctx, deletedBefore); err != nil {
+			return fmt.Errorf("purge publishers: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return PurgeResult{}, err
+	}
+	return result, nil
+}
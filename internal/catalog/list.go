@@ -0,0 +1,254 @@
+package catalog
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// DATETIME limits used for open TimeRange bounds.
+var (
+	minTime = time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	maxTime = time.Date(9999, time.December, 31, 23, 59, 59, 999999000, time.UTC)
+)
+
+// TimeRange is the half-open interval [From, To). A zero bound leaves that
+// side of the range open.
+type TimeRange struct {
+	From time.Time
+	To   time.Time
+}
+
+func (r TimeRange) bounds() (time.Time, time.Time) {
+	from, to := r.From, r.To
+	if from.IsZero() {
+		from = minTime
+	}
+	if to.IsZero() {
+		to = maxTime
+	}
+	return from, to
+}
+
+// ListFilter restricts list results by the created_at and updated_at
+// columns. Author-book links are only filtered by Created.
+type ListFilter struct {
+	Created TimeRange
+	Updated TimeRange
+	// IncludeDeleted also lists soft-deleted rows. Author-book links of
+	// soft-deleted authors or books are never listed.
+	IncludeDeleted bool
+}
+
+func (s *Service) ListAuthors(ctx context.Context, req pagination.Request, filter ListFilter) (pagination.Page[sqlc.Author], error) {
+	after, err := req.After(1)
+	if err != nil {
+		return pagination.Page[sqlc.Author]{}, err
+	}
+
+	createdFrom, createdTo := filter.Created.bounds()
+	updatedFrom, updatedTo := filter.Updated.bounds()
+	params := sqlc.ListAuthorsPageParams{
+		AfterUuid:   after[0],
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		UpdatedFrom: updatedFrom,
+		UpdatedTo:   updatedTo,
+		Limit:       req.QueryLimit(),
+	}
+	var rows []sqlc.Author
+	if filter.IncludeDeleted {
+		rows, err = s.queries.ListAuthorsPageIncludingDeleted(ctx, sqlc.ListAuthorsPageIncludingDeletedParams(params))
+	} else {
+		rows, err = s.queries.ListAuthorsPage(ctx, params)
+	}
+	if err != nil {
+		return pagination.Page[sqlc.Author]{}, dberr.Translate(err)
+	}
+
+	return pagination.NewPage(req, rows, func(a sqlc.Author) []binuuid.UUID {
+		return []binuuid.UUID{a.Uuid}
+	}), nil
+}
+
+func (s *Service) ListPublishers(ctx context.Context, req pagination.Request, filter ListFilter) (pagination.Page[sqlc.Publisher], error) {
+	after, err := req.After(1)
+	if err != nil {
+		return pagination.Page[sqlc.Publisher]{}, err
+	}
+
+	createdFrom, createdTo := filter.Created.bounds()
+	updatedFrom, updatedTo := filter.Updated.bounds()
+	params := sqlc.ListPublishersPageParams{
+		AfterUuid:   after[0],
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		UpdatedFrom: updatedFrom,
+		UpdatedTo:   updatedTo,
+		Limit:       req.QueryLimit(),
+	}
+	var rows []sqlc.Publisher
+	if filter.IncludeDeleted {
+		rows, err = s.queries.ListPublishersPageIncludingDeleted(ctx, sqlc.ListPublishersPageIncludingDeletedParams(params))
+	} else {
+		rows, err = s.queries.ListPublishersPage(ctx, params)
+	}
+	if err != nil {
+		return pagination.Page[sqlc.Publisher]{}, dberr.Translate(err)
+	}
+
+	return pagination.NewPage(req, rows, func(p sqlc.Publisher) []binuuid.UUID {
+		return []binuuid.UUID{p.Uuid}
+	}), nil
+}
+
+func (s *Service) ListBooks(ctx context.Context, req pagination.Request, filter ListFilter) (pagination.Page[sqlc.Book], error) {
+	after, err := req.After(1)
+	if err != nil {
+		return pagination.Page[sqlc.Book]{}, err
+	}
+
+	createdFrom, createdTo := filter.Created.bounds()
+	updatedFrom, updatedTo := filter.Updated.bounds()
+	params := sqlc.ListBooksPageParams{
+		AfterUuid:   after[0],
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		UpdatedFrom: updatedFrom,
+		UpdatedTo:   updatedTo,
+		Limit:       req.QueryLimit(),
+	}
+	var rows []sqlc.Book
+	if filter.IncludeDeleted {
+		rows, err = s.queries.ListBooksPageIncludingDeleted(ctx, sqlc.ListBooksPageIncludingDeletedParams(params))
+	} else {
+		rows, err = s.queries.ListBooksPage(ctx, params)
+	}
+	if err != nil {
+		return pagination.Page[sqlc.Book]{}, dberr.Translate(err)
+	}
+
+	return pagination.NewPage(req, rows, func(b sqlc.Book) []binuuid.UUID {
+		return []binuuid.UUID{b.Uuid}
+	}), nil
+}
+
+func (s *Service) ListAuthorBooks(ctx context.Context, req pagination.Request, filter ListFilter) (pagination.Page[sqlc.ListAuthorBooksPageRow], error) {
+	after, err := req.After(2)
+	if err != nil {
+		return pagination.Page[sqlc.ListAuthorBooksPageRow]{}, err
+	}
+
+	createdFrom, createdTo := filter.Created.bounds()
+	rows, err := s.queries.ListAuthorBooksPage(ctx, sqlc.ListAuthorBooksPageParams{
+		AfterAuthorUuid: after[0],
+		AfterBookUuid:   after[1],
+		CreatedFrom:     createdFrom,
+		CreatedTo:       createdTo,
+		Limit:           req.QueryLimit(),
+	})
+	if err != nil {
+		return pagination.Page[sqlc.ListAuthorBooksPageRow]{}, dberr.Translate(err)
+	}
+
+	return pagination.NewPage(req, rows, func(ab sqlc.ListAuthorBooksPageRow) []binuuid.UUID {
+		return []binuuid.UUID{ab.AuthorUuid, ab.BookUuid}
+	}), nil
+}
+
+// ListBooksByAuthor lists the live books linked to an author. It does not
+// check that the author exists.
+func (s *Service) ListBooksByAuthor(ctx context.Context, authorUuid binuuid.UUID, req pagination.Request) (pagination.Page[sqlc.Book], error) {
+	after, err := req.After(1)
+	if err != nil {
+		return pagination.Page[sqlc.Book]{}, err
+	}
+
+	rows, err := s.queries.ListBooksByAuthor(ctx, sqlc.ListBooksByAuthorParams{
+		AuthorUuid: authorUuid,
+		AfterUuid:  after[0],
+		Limit:      req.QueryLimit(),
+	})
+	if err != nil {
+		return pagination.Page[sqlc.Book]{}, dberr.Translate(err)
+	}
+
+	return pagination.NewPage(req, rows, func(b sqlc.Book) []binuuid.UUID {
+		return []binuuid.UUID{b.Uuid}
+	}), nil
+}
+
+// ListAuthorsByBook lists the live authors linked to a book. It does not
+// check that the book exists.
+func (s *Service) ListAuthorsByBook(ctx context.Context, bookUuid binuuid.UUID, req pagination.Request) (pagination.Page[sqlc.Author], error) {
+	after, err := req.After(1)
+	if err != nil {
+		return pagination.Page[sqlc.Author]{}, err
+	}
+
+	rows, err := s.queries.ListAuthorsByBook(ctx, sqlc.ListAuthorsByBookParams{
+		BookUuid:  bookUuid,
+		AfterUuid: after[0],
+		Limit:     req.QueryLimit(),
+	})
+	if err != nil {
+		return pagination.Page[sqlc.Author]{}, dberr.Translate(err)
+	}
+
+	return pagination.NewPage(req, rows, func(a sqlc.Author) []binuuid.UUID {
+		return []binuuid.UUID{a.Uuid}
+	}), nil
+}
+
+// BookSummary is a live book with the name of its publisher and the names of
+// its live authors.
+type BookSummary struct {
+	sqlc.ListBookSummariesPageRow
+	// AuthorNames is sorted by name.
+	AuthorNames []string
+}
+
+// ListBookSummaries lists live books like ListBooks, denormalized with the
+// names of their publisher and authors. Each page takes two queries.
+func (s *Service) ListBookSummaries(ctx context.Context, req pagination.Request) (pagination.Page[BookSummary], error) {
+	after, err := req.After(1)
+	if err != nil {
+		return pagination.Page[BookSummary]{}, err
+	}
+
+	rows, err := s.queries.ListBookSummariesPage(ctx, sqlc.ListBookSummariesPageParams{
+		AfterUuid: after[0],
+		Limit:     req.QueryLimit(),
+	})
+	if err != nil {
+		return pagination.Page[BookSummary]{}, dberr.Translate(err)
+	}
+	page := pagination.NewPage(req, rows, func(b sqlc.ListBookSummariesPageRow) []binuuid.UUID {
+		return []binuuid.UUID{b.Uuid}
+	})
+
+	summaries := make([]BookSummary, len(page.Items))
+	bookUuids := make([]binuuid.UUID, len(page.Items))
+	index := make(map[binuuid.UUID]int, len(page.Items))
+	for i, row := range page.Items {
+		summaries[i].ListBookSummariesPageRow = row
+		bookUuids[i] = row.Uuid
+		index[row.Uuid] = i
+	}
+	if len(bookUuids) > 0 {
+		names, err := s.queries.ListAuthorNamesOfBooks(ctx, bookUuids)
+		if err != nil {
+			return pagination.Page[BookSummary]{}, dberr.Translate(err)
+		}
+		for _, name := range names {
+			i := index[name.BookUuid]
+			summaries[i].AuthorNames = append(summaries[i].AuthorNames, name.AuthorName)
+		}
+	}
+
+	return pagination.Page[BookSummary]{Items: summaries, NextCursor: page.NextCursor}, nil
+}
@@ -0,0 +1,70 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrInvalidSearch reports a search query that is not a valid boolean-mode
+// expression, e.g. a lone "@" or an unbalanced "(".
+var ErrInvalidSearch = errors.New("invalid search query")
+
+// codeParseError is the error MySQL returns for a malformed boolean-mode
+// expression.
+const codeParseError = 1064
+
+// SearchBooks runs a boolean-mode full-text search over book titles.
+// Results are ranked by relevance, so pages use offset cursors.
+func (s *Service) SearchBooks(ctx context.Context, query string, req pagination.Request) (pagination.Page[sqlc.SearchBooksRow], error) {
+	offset, err := req.Offset()
+	if err != nil {
+		return pagination.Page[sqlc.SearchBooksRow]{}, err
+	}
+
+	rows, err := s.queries.SearchBooks(ctx, sqlc.SearchBooksParams{
+		Query:  query,
+		Limit:  req.QueryLimit(),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return pagination.Page[sqlc.SearchBooksRow]{}, searchError(err)
+	}
+
+	return pagination.NewOffsetPage(req, offset, rows), nil
+}
+
+// SearchAuthors runs a boolean-mode full-text search over author names and
+// bios.
+func (s *Service) SearchAuthors(ctx context.Context, query string, req pagination.Request) (pagination.Page[sqlc.SearchAuthorsRow], error) {
+	offset, err := req.Offset()
+	if err != nil {
+		return pagination.Page[sqlc.SearchAuthorsRow]{}, err
+	}
+
+	rows, err := s.queries.SearchAuthors(ctx, sqlc.SearchAuthorsParams{
+		Query:  query,
+		Limit:  req.QueryLimit(),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return pagination.Page[sqlc.SearchAuthorsRow]{}, searchError(err)
+	}
+
+	return pagination.NewOffsetPage(req, offset, rows), nil
+}
+
+// searchError translates an error of a search query. The statements are
+// fixed, so a syntax error can only come from the user's query.
+func searchError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == codeParseError {
+		return fmt.Errorf("%w: %w", ErrInvalidSearch, err)
+	}
+	return dberr.Translate(err)
+}
@@ -0,0 +1,59 @@
+package catalog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/go-sql-driver/mysql"
+)
+
+// failingDB fails every query with err.
+type failingDB struct {
+	sqlc.DBTX
+	err error
+}
+
+func (db failingDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, db.err
+}
+
+func TestSearchError(t *testing.T) {
+	errConn := errors.New("connection refused")
+
+	tests := []struct {
+		scenario string
+		input    error
+		expected error
+	}{
+		{
+			scenario: "malformed query",
+			input:    &mysql.MySQLError{Number: 1064, Message: "syntax error, unexpected '@'"},
+			expected: ErrInvalidSearch,
+		},
+		{
+			scenario: "other error",
+			input:    errConn,
+			expected: errConn,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			service := New(failingDB{err: tt.input})
+
+			_, err := service.SearchBooks(context.Background(), "@", pagination.Request{})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+			_, err = service.SearchAuthors(context.Background(), "@", pagination.Request{})
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
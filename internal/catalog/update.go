@@ -0,0 +1,86 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// UpdateAuthor applies arg when arg.Version is still the author's version
+// and returns the updated author. It fails with dberr.ErrConflict when the
+// author has been changed since that version was read and with
+// dberr.ErrNotFound when there is no live author.
+func (s *Service) UpdateAuthor(ctx context.Context, arg sqlc.UpdateAuthorParams) (sqlc.Author, error) {
+	var author sqlc.Author
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		n, err := q.UpdateAuthor(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("update author %s: %w", arg.Uuid, err)
+		}
+		author, err = q.GetAuthor(ctx, arg.Uuid)
+		if err != nil {
+			return fmt.Errorf("get author %s: %w", arg.Uuid, err)
+		}
+		return checkVersion(n, "author", arg.Uuid, arg.Version, author.Version)
+	})
+	if err != nil {
+		return sqlc.Author{}, err
+	}
+	return author, nil
+}
+
+// UpdatePublisher is UpdateAuthor for publishers.
+func (s *Service) UpdatePublisher(ctx context.Context, arg sqlc.UpdatePublisherParams) (sqlc.Publisher, error) {
+	var publisher sqlc.Publisher
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		n, err := q.UpdatePublisher(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("update publisher %s: %w", arg.Uuid, err)
+		}
+		publisher, err = q.GetPublisher(ctx, arg.Uuid)
+		if err != nil {
+			return fmt.Errorf("get publisher %s: %w", arg.Uuid, err)
+		}
+		return checkVersion(n, "publisher", arg.Uuid, arg.Version, publisher.Version)
+	})
+	if err != nil {
+		return sqlc.Publisher{}, err
+	}
+	return publisher, nil
+}
+
+// UpdateBook is UpdateAuthor for books.
+func (s *Service) UpdateBook(ctx context.Context, arg sqlc.UpdateBookParams) (sqlc.Book, error) {
+	var book sqlc.Book
+
+	err := s.InTx(ctx, func(q *sqlc.Queries) error {
+		n, err := q.UpdateBook(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("update book %s: %w", arg.Uuid, err)
+		}
+		book, err = q.GetBook(ctx, arg.Uuid)
+		if err != nil {
+			return fmt.Errorf("get book %s: %w", arg.Uuid, err)
+		}
+		return checkVersion(n, "book", arg.Uuid, arg.Version, book.Version)
+	})
+	if err != nil {
+		return sqlc.Book{}, err
+	}
+	return book, nil
+}
+
+// checkVersion tells a stale version apart from a missing row after an
+// update matched n rows. A missing row has already been reported by the
+// Get query that follows the update.
+func checkVersion(n int64, kind string, uuid binuuid.UUID, want, got uint32) error {
+	if n > 0 {
+		return nil
+	}
+	return fmt.Errorf("%s %s has version %d, not %d: %w", kind, uuid, got, want, dberr.ErrConflict)
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type author struct {
+	Uuid      binuuid.UUID `json:"uuid"`
+	Name      string       `json:"name"`
+	Bio       *string      `json:"bio"`
+	Version   uint32       `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	DeletedAt *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func newAuthor(a sqlc.Author) author {
+	return author{
+		Uuid:      a.Uuid,
+		Name:      a.Name,
+		Bio:       stringPtr(a.Bio),
+		Version:   a.Version,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+		DeletedAt: timePtr(a.DeletedAt),
+	}
+}
+
+type deleteAuthorResult struct {
+	AuthorBooks int64 `json:"author_books"`
+}
+
+func (c *CLI) createAuthor(ctx context.Context, args []string) error {
+	fs := c.flagSet("author create")
+	authorUuid := fs.uuidFlag("uuid", "uuid of the author (default: generated)")
+	name := fs.String("name", "", "name of the author (required)")
+	bio := fs.String("bio", "", "biography of the author")
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		return errRequired("name")
+	}
+	if !fs.isSet("uuid") {
+		*authorUuid = binuuid.New()
+	}
+
+	err = c.queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{
+		Uuid: *authorUuid,
+		Name: *name,
+		Bio:  nullString(*bio),
+	})
+	if err != nil {
+		return dberr.Translate(err)
+	}
+
+	a, err := c.queries.GetAuthor(ctx, *authorUuid)
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	return p.item(newAuthor(a))
+}
+
+func (c *CLI) getAuthor(ctx context.Context, args []string) error {
+	fs := c.flagSet("author get")
+	includeDeleted := fs.Bool("include-deleted", false, "also find a deleted author")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	get := c.queries.GetAuthor
+	if *includeDeleted {
+		get = c.queries.GetAuthorIncludingDeleted
+	}
+	a, err := get(ctx, uuids[0])
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	return p.item(newAuthor(a))
+}
+
+func (c *CLI) listAuthors(ctx context.Context, args []string) error {
+	fs := c.flagSet("author list")
+	req := fs.pageFlags()
+	includeDeleted := fs.Bool("include-deleted", false, "also list deleted authors")
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.catalog.ListAuthors(ctx, *req, catalog.ListFilter{IncludeDeleted: *includeDeleted})
+	if err != nil {
+		return err
+	}
+	return p.list(convert(page.Items, newAuthor), page.NextCursor)
+}
+
+func (c *CLI) updateAuthor(ctx context.Context, args []string) error {
+	fs := c.flagSet("author update")
+	name := fs.String("name", "", "new name")
+	bio := fs.String("bio", "", "new biography, empty to clear it")
+	version := fs.Uint("version", 0, "version the update is based on (default: the current version)")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.queries.GetAuthor(ctx, uuids[0])
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	arg := sqlc.UpdateAuthorParams{
+		Name:    current.Name,
+		Bio:     current.Bio,
+		Uuid:    current.Uuid,
+		Version: current.Version,
+	}
+	if fs.isSet("name") {
+		if *name == "" {
+			return errRequired("name")
+		}
+		arg.Name = *name
+	}
+	if fs.isSet("bio") {
+		arg.Bio = nullString(*bio)
+	}
+	if fs.isSet("version") {
+		arg.Version = uint32(*version)
+	}
+
+	a, err := c.catalog.UpdateAuthor(ctx, arg)
+	if err != nil {
+		return err
+	}
+	return p.item(newAuthor(a))
+}
+
+func (c *CLI) deleteAuthor(ctx context.Context, args []string) error {
+	fs := c.flagSet("author delete")
+	cascade := fs.Bool("cascade", false, "also remove the author's links to books")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	if !*cascade {
+		return c.catalog.DeleteAuthor(ctx, uuids[0])
+	}
+	result, err := c.catalog.DeleteAuthorCascade(ctx, uuids[0])
+	if err != nil {
+		return err
+	}
+	return p.item(deleteAuthorResult{AuthorBooks: result.AuthorBooks})
+}
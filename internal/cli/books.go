@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type book struct {
+	Uuid          binuuid.UUID `json:"uuid"`
+	Title         string       `json:"title"`
+	PublisherUuid binuuid.UUID `json:"publisher_uuid"`
+	Version       uint32       `json:"version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	DeletedAt     *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func newBook(b sqlc.Book) book {
+	return book{
+		Uuid:          b.Uuid,
+		Title:         b.Title,
+		PublisherUuid: b.PublisherUuid,
+		Version:       b.Version,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+		DeletedAt:     timePtr(b.DeletedAt),
+	}
+}
+
+func (c *CLI) createBook(ctx context.Context, args []string) error {
+	fs := c.flagSet("book create")
+	bookUuid := fs.uuidFlag("uuid", "uuid of the book (default: generated)")
+	title := fs.String("title", "", "title of the book (required)")
+	publisherUuid := fs.uuidFlag("publisher-uuid", "uuid of the publisher (required)")
+	var authorUuids []binuuid.UUID
+	fs.Func("author-uuid", "uuid of an author to link, repeatable", func(s string) error {
+		id, err := binuuid.Parse(s)
+		authorUuids = append(authorUuids, id)
+		return err
+	})
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+	if *title == "" {
+		return errRequired("title")
+	}
+	if !fs.isSet("publisher-uuid") {
+		return errRequired("publisher-uuid")
+	}
+	if !fs.isSet("uuid") {
+		*bookUuid = binuuid.New()
+	}
+
+	detail, err := c.catalog.CreateBook(ctx, catalog.CreateBookParams{
+		Uuid:          *bookUuid,
+		Title:         *title,
+		PublisherUuid: *publisherUuid,
+		AuthorUuids:   authorUuids,
+	})
+	if err != nil {
+		return err
+	}
+	return p.item(newBook(detail.Book))
+}
+
+func (c *CLI) getBook(ctx context.Context, args []string) error {
+	fs := c.flagSet("book get")
+	includeDeleted := fs.Bool("include-deleted", false, "also find a deleted book")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	get := c.queries.GetBook
+	if *includeDeleted {
+		get = c.queries.GetBookIncludingDeleted
+	}
+	b, err := get(ctx, uuids[0])
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	return p.item(newBook(b))
+}
+
+func (c *CLI) listBooks(ctx context.Context, args []string) error {
+	fs := c.flagSet("book list")
+	req := fs.pageFlags()
+	includeDeleted := fs.Bool("include-deleted", false, "also list deleted books")
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.catalog.ListBooks(ctx, *req, catalog.ListFilter{IncludeDeleted: *includeDeleted})
+	if err != nil {
+		return err
+	}
+	return p.list(convert(page.Items, newBook), page.NextCursor)
+}
+
+func (c *CLI) updateBook(ctx context.Context, args []string) error {
+	fs := c.flagSet("book update")
+	title := fs.String("title", "", "new title")
+	version := fs.Uint("version", 0, "version the update is based on (default: the current version)")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.queries.GetBook(ctx, uuids[0])
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	arg := sqlc.UpdateBookParams{
+		Title:   current.Title,
+		Uuid:    current.Uuid,
+		Version: current.Version,
+	}
+	if fs.isSet("title") {
+		if *title == "" {
+			return errRequired("title")
+		}
+		arg.Title = *title
+	}
+	if fs.isSet("version") {
+		arg.Version = uint32(*version)
+	}
+
+	b, err := c.catalog.UpdateBook(ctx, arg)
+	if err != nil {
+		return err
+	}
+	return p.item(newBook(b))
+}
+
+func (c *CLI) deleteBook(ctx context.Context, args []string) error {
+	fs := c.flagSet("book delete")
+	uuids, _, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+	return c.catalog.DeleteBook(ctx, uuids[0])
+}
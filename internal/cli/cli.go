@@ -0,0 +1,218 @@
+// Package cli implements the catalog subcommands of the command-line tool:
+//
+//	author    create | get | list | update | delete
+//	publisher create | get | list | update | delete
+//	book      create | get | list | update | delete
+//	link      add | remove | list
+//
+// Results are printed as a table, or with -output json in the shapes of
+// the HTTP API so that scripts can use either.
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type CLI struct {
+	queries *sqlc.Queries
+	catalog *catalog.Service
+	stdout  io.Writer
+}
+
+// New returns a CLI that runs its queries against db and prints results to
+// stdout.
+func New(db sqlc.DBTX, stdout io.Writer) *CLI {
+	return &CLI{
+		queries: sqlc.New(db),
+		catalog: catalog.New(db),
+		stdout:  stdout,
+	}
+}
+
+type command func(ctx context.Context, args []string) error
+
+func (c *CLI) commands() map[string]map[string]command {
+	return map[string]map[string]command{
+		"author": {
+			"create": c.createAuthor,
+			"get":    c.getAuthor,
+			"list":   c.listAuthors,
+			"update": c.updateAuthor,
+			"delete": c.deleteAuthor,
+		},
+		"publisher": {
+			"create": c.createPublisher,
+			"get":    c.getPublisher,
+			"list":   c.listPublishers,
+			"update": c.updatePublisher,
+			"delete": c.deletePublisher,
+		},
+		"book": {
+			"create": c.createBook,
+			"get":    c.getBook,
+			"list":   c.listBooks,
+			"update": c.updateBook,
+			"delete": c.deleteBook,
+		},
+		"link": {
+			"add":    c.addLink,
+			"remove": c.removeLink,
+			"list":   c.listLinks,
+		},
+	}
+}
+
+// Handles reports whether name is a subcommand of the CLI.
+func Handles(name string) bool {
+	_, ok := (&CLI{}).commands()[name]
+	return ok
+}
+
+// Run runs the subcommand named by args, e.g. "author get <uuid>".
+func (c *CLI) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: author|publisher|book|link <command> [arguments]")
+	}
+	verbs, ok := c.commands()[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: %s %s [arguments]", args[0], strings.Join(sortedKeys(verbs), "|"))
+	}
+	run, ok := verbs[args[1]]
+	if !ok {
+		return fmt.Errorf("unknown command %q %q", args[0], args[1])
+	}
+	return run(ctx, args[2:])
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flagSet is a flag.FlagSet with the flags every command shares.
+type flagSet struct {
+	*flag.FlagSet
+	stdout io.Writer
+	output *string
+}
+
+func (c *CLI) flagSet(name string) *flagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	return &flagSet{
+		FlagSet: fs,
+		stdout:  c.stdout,
+		output:  fs.String("output", string(formatTable), "output format: table or json"),
+	}
+}
+
+// parse parses args, which must start with the n uuid arguments of the
+// command, followed by flags.
+func (fs *flagSet) parse(args []string, n int) ([]binuuid.UUID, *printer, error) {
+	if len(args) < n || slices.ContainsFunc(args[:n], func(arg string) bool { return strings.HasPrefix(arg, "-") }) {
+		return nil, nil, fmt.Errorf("%s: want %d uuid arguments before the flags", fs.Name(), n)
+	}
+	uuids := make([]binuuid.UUID, n)
+	for i, arg := range args[:n] {
+		var err error
+		if uuids[i], err = binuuid.Parse(arg); err != nil {
+			return nil, nil, fmt.Errorf("%s: invalid uuid %q", fs.Name(), arg)
+		}
+	}
+
+	if err := fs.Parse(args[n:]); err != nil {
+		return nil, nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, nil, fmt.Errorf("%s: unexpected arguments %q", fs.Name(), fs.Args())
+	}
+
+	p, err := newPrinter(fs.stdout, format(*fs.output))
+	if err != nil {
+		return nil, nil, err
+	}
+	return uuids, p, nil
+}
+
+// isSet reports whether the flag name was passed on the command line.
+func (fs *flagSet) isSet(name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// pageFlags adds the pagination flags of list commands.
+func (fs *flagSet) pageFlags() *pagination.Request {
+	var req pagination.Request
+	fs.IntVar(&req.Limit, "limit", pagination.DefaultLimit, "page size")
+	fs.StringVar(&req.Cursor, "cursor", "", "next_cursor of the previous page")
+	return &req
+}
+
+// uuidFlag adds an optional uuid flag.
+func (fs *flagSet) uuidFlag(name, usage string) *binuuid.UUID {
+	var id binuuid.UUID
+	fs.Func(name, usage, func(s string) error {
+		var err error
+		id, err = binuuid.Parse(s)
+		if err == nil && id == binuuid.Nil {
+			err = binuuid.ErrNil
+		}
+		return err
+	})
+	return &id
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func errRequired(flag string) error {
+	return fmt.Errorf("-%s is required", flag)
+}
+
+func convert[S, T any](items []S, fn func(S) T) []T {
+	out := make([]T, 0, len(items))
+	for _, item := range items {
+		out = append(out, fn(item))
+	}
+	return out
+}
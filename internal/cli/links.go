@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type authorBook struct {
+	AuthorUuid binuuid.UUID `json:"author_uuid"`
+	BookUuid   binuuid.UUID `json:"book_uuid"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+func newAuthorBook(ab sqlc.AuthorBook) authorBook {
+	return authorBook{
+		AuthorUuid: ab.AuthorUuid,
+		BookUuid:   ab.BookUuid,
+		CreatedAt:  ab.CreatedAt,
+		UpdatedAt:  ab.UpdatedAt,
+	}
+}
+
+type authorBookRow struct {
+	AuthorUuid binuuid.UUID `json:"author_uuid"`
+	AuthorName string       `json:"author_name"`
+	BookUuid   binuuid.UUID `json:"book_uuid"`
+	BookTitle  string       `json:"book_title"`
+}
+
+func newAuthorBookRow(ab sqlc.ListAuthorBooksPageRow) authorBookRow {
+	return authorBookRow{
+		AuthorUuid: ab.AuthorUuid,
+		AuthorName: ab.AuthorName,
+		BookUuid:   ab.BookUuid,
+		BookTitle:  ab.BookTitle,
+	}
+}
+
+func (c *CLI) addLink(ctx context.Context, args []string) error {
+	fs := c.flagSet("link add")
+	uuids, p, err := fs.parse(args, 2)
+	if err != nil {
+		return err
+	}
+
+	params := sqlc.CreateAuthorBookParams{AuthorUuid: uuids[0], BookUuid: uuids[1]}
+	if err := c.queries.CreateAuthorBook(ctx, params); err != nil {
+		return dberr.Translate(err)
+	}
+
+	ab, err := c.queries.GetAuthorBook(ctx, sqlc.GetAuthorBookParams(params))
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	return p.item(newAuthorBook(ab))
+}
+
+func (c *CLI) removeLink(ctx context.Context, args []string) error {
+	fs := c.flagSet("link remove")
+	uuids, _, err := fs.parse(args, 2)
+	if err != nil {
+		return err
+	}
+
+	return dberr.CheckAffected(c.queries.DeleteAuthorBook(ctx, sqlc.DeleteAuthorBookParams{
+		AuthorUuid: uuids[0],
+		BookUuid:   uuids[1],
+	}))
+}
+
+func (c *CLI) listLinks(ctx context.Context, args []string) error {
+	fs := c.flagSet("link list")
+	req := fs.pageFlags()
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.catalog.ListAuthorBooks(ctx, *req, catalog.ListFilter{})
+	if err != nil {
+		return err
+	}
+	return p.list(convert(page.Items, newAuthorBookRow), page.NextCursor)
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type format string
+
+const (
+	formatTable format = "table"
+	formatJSON  format = "json"
+)
+
+// printer prints results. Items are structs whose json tags name their
+// columns; see cell for the supported field types.
+type printer struct {
+	w      io.Writer
+	format format
+}
+
+func newPrinter(w io.Writer, f format) (*printer, error) {
+	switch f {
+	case formatTable, formatJSON:
+		return &printer{w: w, format: f}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q, want table or json", f)
+}
+
+// item prints a single struct: as a JSON object, or as a two-column table of
+// field names and values. Empty omitempty fields are left out of the table.
+func (p *printer) item(v any) error {
+	if p.format == formatJSON {
+		return p.json(v)
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	value := reflect.ValueOf(v)
+	for _, field := range columns(value.Type()) {
+		fv := value.Field(field.index)
+		if field.omitEmpty && fv.IsZero() {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\n", field.header, cell(fv))
+	}
+	return tw.Flush()
+}
+
+// list prints a page of structs: as {"items": [...], "next_cursor": "..."},
+// like the HTTP API, or as a table with one row per item followed by the
+// next cursor.
+func (p *printer) list(items any, nextCursor string) error {
+	value := reflect.ValueOf(items)
+	if p.format == formatJSON {
+		if value.IsNil() {
+			value = reflect.MakeSlice(value.Type(), 0, 0)
+		}
+		return p.json(struct {
+			Items      any    `json:"items"`
+			NextCursor string `json:"next_cursor,omitempty"`
+		}{value.Interface(), nextCursor})
+	}
+
+	tw := tabwriter.NewWriter(p.w, 0, 4, 2, ' ', 0)
+	fields := columns(value.Type().Elem())
+	headers := make([]string, len(fields))
+	for i, field := range fields {
+		headers[i] = field.header
+	}
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+
+	cells := make([]string, len(fields))
+	for i := range value.Len() {
+		for j, field := range fields {
+			cells[j] = cell(value.Index(i).Field(field.index))
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if nextCursor != "" {
+		_, err := fmt.Fprintf(p.w, "next cursor: %s\n", nextCursor)
+		return err
+	}
+	return nil
+}
+
+func (p *printer) json(v any) error {
+	enc := json.NewEncoder(p.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+type column struct {
+	index     int
+	header    string
+	omitEmpty bool
+}
+
+func columns(t reflect.Type) []column {
+	var cols []column
+	for i := range t.NumField() {
+		name, opts, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		cols = append(cols, column{
+			index:     i,
+			header:    strings.ToUpper(name),
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+	return cols
+}
+
+// cell formats a field for a table. Nil pointers and slices print as "-",
+// slices as comma-separated lists and times in RFC 3339.
+func cell(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return "-"
+		}
+		return cell(v.Elem())
+	case reflect.Slice:
+		if v.Len() == 0 {
+			return "-"
+		}
+		cells := make([]string, v.Len())
+		for i := range cells {
+			cells[i] = cell(v.Index(i))
+		}
+		return strings.Join(cells, ", ")
+	}
+
+	switch x := v.Interface().(type) {
+	case time.Time:
+		return x.Format(time.RFC3339)
+	case encoding.TextMarshaler:
+		text, err := x.MarshalText()
+		if err != nil {
+			return "?"
+		}
+		return string(text)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+type testRow struct {
+	Uuid      binuuid.UUID `json:"uuid"`
+	Name      string       `json:"name"`
+	Bio       *string      `json:"bio"`
+	Tags      []string     `json:"tags"`
+	CreatedAt time.Time    `json:"created_at"`
+	DeletedAt *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func TestPrinterItem(t *testing.T) {
+	bio := "Unix"
+	row := testRow{
+		Uuid:      binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+		Name:      "Ken Thompson",
+		Bio:       &bio,
+		Tags:      []string{"a", "b"},
+		CreatedAt: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	tests := []struct {
+		scenario string
+		input    format
+		expected string
+	}{
+		{
+			scenario: "table",
+			input:    formatTable,
+			expected: `UUID        0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10
+NAME        Ken Thompson
+BIO         Unix
+TAGS        a, b
+CREATED_AT  2024-01-02T03:04:05Z
+`,
+		},
+		{
+			scenario: "json",
+			input:    formatJSON,
+			expected: `{
+  "uuid": "0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10",
+  "name": "Ken Thompson",
+  "bio": "Unix",
+  "tags": [
+    "a",
+    "b"
+  ],
+  "created_at": "2024-01-02T03:04:05Z"
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			var b strings.Builder
+			p, err := newPrinter(&b, tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.item(row); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.expected {
+				t.Errorf("got=%v, want=%v", b.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestPrinterList(t *testing.T) {
+	rows := []testRow{
+		{
+			Uuid:      binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+			Name:      "Rob Pike",
+			CreatedAt: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+		},
+	}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			format     format
+			rows       []testRow
+			nextCursor string
+		}
+		expected string
+	}{
+		{
+			scenario: "table",
+			input: struct {
+				format     format
+				rows       []testRow
+				nextCursor string
+			}{
+				format:     formatTable,
+				rows:       rows,
+				nextCursor: "AQ",
+			},
+			expected: `UUID                                  NAME      BIO  TAGS  CREATED_AT            DELETED_AT
+0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10  Rob Pike  -    -     2024-01-02T03:04:05Z  -
+next cursor: AQ
+`,
+		},
+		{
+			scenario: "empty json",
+			input: struct {
+				format     format
+				rows       []testRow
+				nextCursor string
+			}{
+				format: formatJSON,
+				rows:   nil,
+			},
+			expected: `{
+  "items": []
+}
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			var b strings.Builder
+			p, err := newPrinter(&b, tt.input.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.list(tt.input.rows, tt.input.nextCursor); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.expected {
+				t.Errorf("got=%v, want=%v", b.String(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type publisher struct {
+	Uuid      binuuid.UUID `json:"uuid"`
+	Name      string       `json:"name"`
+	Version   uint32       `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	DeletedAt *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func newPublisher(p sqlc.Publisher) publisher {
+	return publisher{
+		Uuid:      p.Uuid,
+		Name:      p.Name,
+		Version:   p.Version,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+		DeletedAt: timePtr(p.DeletedAt),
+	}
+}
+
+type deletePublisherResult struct {
+	Books int64 `json:"books"`
+}
+
+func (c *CLI) createPublisher(ctx context.Context, args []string) error {
+	fs := c.flagSet("publisher create")
+	publisherUuid := fs.uuidFlag("uuid", "uuid of the publisher (default: generated)")
+	name := fs.String("name", "", "name of the publisher (required)")
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+	if *name == "" {
+		return errRequired("name")
+	}
+	if !fs.isSet("uuid") {
+		*publisherUuid = binuuid.New()
+	}
+
+	err = c.queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{
+		Uuid: *publisherUuid,
+		Name: *name,
+	})
+	if err != nil {
+		return dberr.Translate(err)
+	}
+
+	pub, err := c.queries.GetPublisher(ctx, *publisherUuid)
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	return p.item(newPublisher(pub))
+}
+
+func (c *CLI) getPublisher(ctx context.Context, args []string) error {
+	fs := c.flagSet("publisher get")
+	includeDeleted := fs.Bool("include-deleted", false, "also find a deleted publisher")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	get := c.queries.GetPublisher
+	if *includeDeleted {
+		get = c.queries.GetPublisherIncludingDeleted
+	}
+	pub, err := get(ctx, uuids[0])
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	return p.item(newPublisher(pub))
+}
+
+func (c *CLI) listPublishers(ctx context.Context, args []string) error {
+	fs := c.flagSet("publisher list")
+	req := fs.pageFlags()
+	includeDeleted := fs.Bool("include-deleted", false, "also list deleted publishers")
+	_, p, err := fs.parse(args, 0)
+	if err != nil {
+		return err
+	}
+
+	page, err := c.catalog.ListPublishers(ctx, *req, catalog.ListFilter{IncludeDeleted: *includeDeleted})
+	if err != nil {
+		return err
+	}
+	return p.list(convert(page.Items, newPublisher), page.NextCursor)
+}
+
+func (c *CLI) updatePublisher(ctx context.Context, args []string) error {
+	fs := c.flagSet("publisher update")
+	name := fs.String("name", "", "new name")
+	version := fs.Uint("version", 0, "version the update is based on (default: the current version)")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	current, err := c.queries.GetPublisher(ctx, uuids[0])
+	if err != nil {
+		return dberr.Translate(err)
+	}
+	arg := sqlc.UpdatePublisherParams{
+		Name:    current.Name,
+		Uuid:    current.Uuid,
+		Version: current.Version,
+	}
+	if fs.isSet("name") {
+		if *name == "" {
+			return errRequired("name")
+		}
+		arg.Name = *name
+	}
+	if fs.isSet("version") {
+		arg.Version = uint32(*version)
+	}
+
+	pub, err := c.catalog.UpdatePublisher(ctx, arg)
+	if err != nil {
+		return err
+	}
+	return p.item(newPublisher(pub))
+}
+
+func (c *CLI) deletePublisher(ctx context.Context, args []string) error {
+	fs := c.flagSet("publisher delete")
+	reassignTo := fs.uuidFlag("reassign-to", "move the publisher's books to this publisher first")
+	uuids, p, err := fs.parse(args, 1)
+	if err != nil {
+		return err
+	}
+
+	if !fs.isSet("reassign-to") {
+		return c.catalog.DeletePublisher(ctx, uuids[0])
+	}
+	result, err := c.catalog.DeletePublisherReassign(ctx, uuids[0], *reassignTo)
+	if err != nil {
+		return err
+	}
+	return p.item(deletePublisherResult{Books: result.Books})
+}
@@ -0,0 +1,349 @@
+// Package config loads the MySQL connection settings used by the
+// application and its tests.
+//
+// Settings are read, in increasing order of precedence, from built-in
+// defaults, a .env file, the process environment and command-line flags.
+// Environment variables share a prefix such as "MYSQL_" or "TEST_MYSQL_".
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	DefaultHost           = "localhost"
+	DefaultPort           = "3306"
+	DefaultCharset        = "utf8mb4"
+	DefaultConnectTimeout = 10 * time.Second
+	DefaultReadTimeout    = 30 * time.Second
+	DefaultWriteTimeout   = 30 * time.Second
+
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 25
+	DefaultConnMaxLifetime = 5 * time.Minute
+	DefaultConnMaxIdleTime = 1 * time.Minute
+	DefaultPingAttempts    = 5
+	DefaultPingBackoff     = 500 * time.Millisecond
+
+	DefaultQueryLogLevel      = slog.LevelDebug
+	DefaultSlowQueryThreshold = 200 * time.Millisecond
+	DefaultQueryLogRedact     = "authors.bio"
+)
+
+type MySQL struct {
+	Database     string
+	User         string
+	Password     string
+	RootPassword string
+	Host         string
+	Port         string
+
+	Charset        string
+	ParseTime      bool
+	ConnectTimeout time.Duration
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// PingAttempts bounds the startup health check; PingBackoff is the
+	// delay before the second attempt and doubles after each failure.
+	PingAttempts int
+	PingBackoff  time.Duration
+
+	// QueryLogLevel is the level queries are logged at, by default below
+	// the INFO threshold of the log; slow and failed queries are logged at
+	// warning level or above. SlowQueryThreshold is
+	// the duration from which a query is slow, 0 meaning never, and
+	// QueryLogRedact lists the comma-separated "table.column"s whose
+	// argument values are not logged.
+	QueryLogLevel      slog.Level
+	SlowQueryThreshold time.Duration
+	QueryLogRedact     string
+
+	// MultiStatements allows several statements per query. It is not read
+	// from the environment; golang-migrate needs it to apply migration files
+	// containing more than one statement.
+	MultiStatements bool
+}
+
+// Default returns the settings used when nothing else is configured.
+func Default() MySQL {
+	return MySQL{
+		Host:           DefaultHost,
+		Port:           DefaultPort,
+		Charset:        DefaultCharset,
+		ParseTime:      true,
+		ConnectTimeout: DefaultConnectTimeout,
+		ReadTimeout:    DefaultReadTimeout,
+		WriteTimeout:   DefaultWriteTimeout,
+
+		MaxOpenConns:    DefaultMaxOpenConns,
+		MaxIdleConns:    DefaultMaxIdleConns,
+		ConnMaxLifetime: DefaultConnMaxLifetime,
+		ConnMaxIdleTime: DefaultConnMaxIdleTime,
+		PingAttempts:    DefaultPingAttempts,
+		PingBackoff:     DefaultPingBackoff,
+
+		QueryLogLevel:      DefaultQueryLogLevel,
+		SlowQueryThreshold: DefaultSlowQueryThreshold,
+		QueryLogRedact:     DefaultQueryLogRedact,
+	}
+}
+
+// Load reads the settings for the given environment variable prefix from
+// the defaults, envFile and the process environment. A missing envFile is
+// not an error. The result is not validated.
+func Load(prefix string, envFile string) (MySQL, error) {
+	cfg := Default()
+
+	lookup, err := envLookup(envFile)
+	if err != nil {
+		return MySQL{}, err
+	}
+
+	if err := cfg.apply(prefix, lookup); err != nil {
+		return MySQL{}, err
+	}
+	return cfg, nil
+}
+
+// Getenv returns the value of key the way Load reads settings: from the
+// process environment or, failing that, envFile. A missing envFile is not
+// an error.
+func Getenv(key string, envFile string) (string, error) {
+	lookup, err := envLookup(envFile)
+	if err != nil {
+		return "", err
+	}
+	v, _ := lookup(key)
+	return v, nil
+}
+
+// envLookup returns a lookup of the process environment falling back to
+// envFile.
+func envLookup(envFile string) (func(key string) (string, bool), error) {
+	fileEnv := map[string]string{}
+	if envFile != "" {
+		var err error
+		fileEnv, err = ReadEnvFile(envFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return func(key string) (string, bool) {
+		if v, ok := os.LookupEnv(key); ok {
+			return v, true
+		}
+		v, ok := fileEnv[key]
+		return v, ok
+	}, nil
+}
+
+// LoadWithFlags loads the settings like Load, then overrides them with the
+// flags found in args and validates the result. The arguments left after
+// the flags are returned.
+func LoadWithFlags(prefix string, envFile string, args []string) (MySQL, []string, error) {
+	cfg, err := Load(prefix, envFile)
+	if err != nil {
+		return MySQL{}, nil, err
+	}
+
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	cfg.RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return MySQL{}, nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return MySQL{}, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+func (c *MySQL) apply(prefix string, lookup func(string) (string, bool)) error {
+	stringVars := map[string]*string{
+		"DATABASE":      &c.Database,
+		"USER":          &c.User,
+		"PASSWORD":      &c.Password,
+		"ROOT_PASSWORD": &c.RootPassword,
+		"HOST":          &c.Host,
+		"TCP_PORT":      &c.Port,
+		"CHARSET":       &c.Charset,
+
+		"QUERY_LOG_REDACT": &c.QueryLogRedact,
+	}
+	for key, dst := range stringVars {
+		if v, ok := lookup(prefix + key); ok && v != "" {
+			*dst = v
+		}
+	}
+
+	if v, ok := lookup(prefix + "QUERY_LOG_LEVEL"); ok && v != "" {
+		if err := c.QueryLogLevel.UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("%sQUERY_LOG_LEVEL: %w", prefix, err)
+		}
+	}
+
+	durationVars := map[string]*time.Duration{
+		"CONNECT_TIMEOUT": &c.ConnectTimeout,
+		"READ_TIMEOUT":    &c.ReadTimeout,
+		"WRITE_TIMEOUT":   &c.WriteTimeout,
+
+		"CONN_MAX_LIFETIME":  &c.ConnMaxLifetime,
+		"CONN_MAX_IDLE_TIME": &c.ConnMaxIdleTime,
+		"PING_BACKOFF":       &c.PingBackoff,
+
+		"SLOW_QUERY_THRESHOLD": &c.SlowQueryThreshold,
+	}
+	for key, dst := range durationVars {
+		v, ok := lookup(prefix + key)
+		if !ok || v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", prefix, key, err)
+		}
+		*dst = d
+	}
+
+	intVars := map[string]*int{
+		"MAX_OPEN_CONNS": &c.MaxOpenConns,
+		"MAX_IDLE_CONNS": &c.MaxIdleConns,
+		"PING_ATTEMPTS":  &c.PingAttempts,
+	}
+	for key, dst := range intVars {
+		v, ok := lookup(prefix + key)
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%s%s: %w", prefix, key, err)
+		}
+		*dst = n
+	}
+
+	if v, ok := lookup(prefix + "PARSE_TIME"); ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%sPARSE_TIME: %w", prefix, err)
+		}
+		c.ParseTime = b
+	}
+
+	return nil
+}
+
+// RegisterFlags registers a flag for every setting on fs, using the current
+// values as defaults.
+func (c *MySQL) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.Database, "mysql-database", c.Database, "MySQL database name")
+	fs.StringVar(&c.User, "mysql-user", c.User, "MySQL user")
+	fs.StringVar(&c.Password, "mysql-password", c.Password, "MySQL password")
+	fs.StringVar(&c.Host, "mysql-host", c.Host, "MySQL host")
+	fs.StringVar(&c.Port, "mysql-port", c.Port, "MySQL TCP port")
+	fs.StringVar(&c.Charset, "mysql-charset", c.Charset, "connection character set")
+	fs.BoolVar(&c.ParseTime, "mysql-parse-time", c.ParseTime, "scan DATE and DATETIME values into time.Time")
+	fs.DurationVar(&c.ConnectTimeout, "mysql-connect-timeout", c.ConnectTimeout, "dial timeout")
+	fs.DurationVar(&c.ReadTimeout, "mysql-read-timeout", c.ReadTimeout, "I/O read timeout")
+	fs.DurationVar(&c.WriteTimeout, "mysql-write-timeout", c.WriteTimeout, "I/O write timeout")
+	fs.IntVar(&c.MaxOpenConns, "mysql-max-open-conns", c.MaxOpenConns, "maximum number of open connections (0 means unlimited)")
+	fs.IntVar(&c.MaxIdleConns, "mysql-max-idle-conns", c.MaxIdleConns, "maximum number of idle connections")
+	fs.DurationVar(&c.ConnMaxLifetime, "mysql-conn-max-lifetime", c.ConnMaxLifetime, "maximum time a connection may be reused (0 means forever)")
+	fs.DurationVar(&c.ConnMaxIdleTime, "mysql-conn-max-idle-time", c.ConnMaxIdleTime, "maximum time a connection may be idle (0 means forever)")
+	fs.IntVar(&c.PingAttempts, "mysql-ping-attempts", c.PingAttempts, "number of startup health check attempts")
+	fs.DurationVar(&c.PingBackoff, "mysql-ping-backoff", c.PingBackoff, "initial delay between startup health check attempts")
+	fs.TextVar(&c.QueryLogLevel, "mysql-query-log-level", c.QueryLogLevel, "level queries are logged at (DEBUG, INFO, WARN or ERROR)")
+	fs.DurationVar(&c.SlowQueryThreshold, "mysql-slow-query-threshold", c.SlowQueryThreshold, "duration from which queries are logged as slow (0 means never)")
+	fs.StringVar(&c.QueryLogRedact, "mysql-query-log-redact", c.QueryLogRedact, "comma-separated table.columns whose query arguments are not logged")
+}
+
+// Validate reports every missing or malformed setting.
+func (c MySQL) Validate() error {
+	var errs []error
+	if c.Database == "" {
+		errs = append(errs, errors.New("mysql database is required"))
+	}
+	if c.User == "" {
+		errs = append(errs, errors.New("mysql user is required"))
+	}
+	if c.Host == "" {
+		errs = append(errs, errors.New("mysql host is required"))
+	}
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		errs = append(errs, fmt.Errorf("mysql port %q is not a valid TCP port", c.Port))
+	}
+	if c.ConnectTimeout < 0 || c.ReadTimeout < 0 || c.WriteTimeout < 0 {
+		errs = append(errs, errors.New("mysql timeouts must not be negative"))
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("mysql connection limits must not be negative"))
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("mysql max idle conns %d exceeds max open conns %d", c.MaxIdleConns, c.MaxOpenConns))
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("mysql connection lifetimes must not be negative"))
+	}
+	if c.PingAttempts < 1 {
+		errs = append(errs, errors.New("mysql ping attempts must be at least 1"))
+	}
+	if c.PingBackoff < 0 {
+		errs = append(errs, errors.New("mysql ping backoff must not be negative"))
+	}
+	if c.SlowQueryThreshold < 0 {
+		errs = append(errs, errors.New("mysql slow query threshold must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// DriverConfig converts the settings to a go-sql-driver/mysql config.
+func (c MySQL) DriverConfig() (*mysql.Config, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = c.User
+	cfg.Passwd = c.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(c.Host, c.Port)
+	cfg.DBName = c.Database
+	cfg.ParseTime = c.ParseTime
+	cfg.Loc = time.UTC
+	cfg.Timeout = c.ConnectTimeout
+	cfg.ReadTimeout = c.ReadTimeout
+	cfg.WriteTimeout = c.WriteTimeout
+	// report matched instead of changed rows, so that :execrows updates
+	// can tell a missing row from an unchanged one
+	cfg.ClientFoundRows = true
+	cfg.MultiStatements = c.MultiStatements
+	// created_at, updated_at and deleted_at are set with CURRENT_TIMESTAMP(6)
+	// in the session time zone, whatever the server's; the session, the
+	// time.Time arguments and the scanned values all use UTC so that they
+	// agree.
+	cfg.Params = map[string]string{"time_zone": "'+00:00'"}
+	if c.Charset != "" {
+		cfg.Params["charset"] = c.Charset
+	}
+	return cfg, nil
+}
+
+// DSN returns the data source name for sql.Open.
+func (c MySQL) DSN() (string, error) {
+	cfg, err := c.DriverConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.FormatDSN(), nil
+}
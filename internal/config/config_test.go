@@ -0,0 +1,335 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadWithFlags(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			envFile string
+			env     map[string]string
+			args    []string
+		}
+		expected MySQL
+	}{
+		{
+			scenario: "defaults and environment",
+			input: struct {
+				envFile string
+				env     map[string]string
+				args    []string
+			}{
+				env: map[string]string{
+					"APP_MYSQL_DATABASE": "db001",
+					"APP_MYSQL_USER":     "user001",
+					"APP_MYSQL_PASSWORD": "pass001",
+				},
+			},
+			expected: MySQL{
+				Database:       "db001",
+				User:           "user001",
+				Password:       "pass001",
+				Host:           DefaultHost,
+				Port:           DefaultPort,
+				Charset:        DefaultCharset,
+				ParseTime:      true,
+				ConnectTimeout: DefaultConnectTimeout,
+				ReadTimeout:    DefaultReadTimeout,
+				WriteTimeout:   DefaultWriteTimeout,
+
+				MaxOpenConns:    DefaultMaxOpenConns,
+				MaxIdleConns:    DefaultMaxIdleConns,
+				ConnMaxLifetime: DefaultConnMaxLifetime,
+				ConnMaxIdleTime: DefaultConnMaxIdleTime,
+				PingAttempts:    DefaultPingAttempts,
+				PingBackoff:     DefaultPingBackoff,
+
+				QueryLogLevel:      DefaultQueryLogLevel,
+				SlowQueryThreshold: DefaultSlowQueryThreshold,
+				QueryLogRedact:     DefaultQueryLogRedact,
+			},
+		},
+		{
+			scenario: "environment overrides env file and flags override environment",
+			input: struct {
+				envFile string
+				env     map[string]string
+				args    []string
+			}{
+				envFile: "APP_MYSQL_DATABASE=file001\nAPP_MYSQL_USER=file001\nAPP_MYSQL_HOST=file001\nAPP_MYSQL_READ_TIMEOUT=5s\n",
+				env: map[string]string{
+					"APP_MYSQL_USER": "env001",
+					"APP_MYSQL_HOST": "env001",
+				},
+				args: []string{"-mysql-host=flag001", "-mysql-port=13306", "-mysql-max-open-conns=10", "-mysql-max-idle-conns=5"},
+			},
+			expected: MySQL{
+				Database:       "file001",
+				User:           "env001",
+				Host:           "flag001",
+				Port:           "13306",
+				Charset:        DefaultCharset,
+				ParseTime:      true,
+				ConnectTimeout: DefaultConnectTimeout,
+				ReadTimeout:    5 * time.Second,
+				WriteTimeout:   DefaultWriteTimeout,
+
+				MaxOpenConns:    10,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: DefaultConnMaxLifetime,
+				ConnMaxIdleTime: DefaultConnMaxIdleTime,
+				PingAttempts:    DefaultPingAttempts,
+				PingBackoff:     DefaultPingBackoff,
+
+				QueryLogLevel:      DefaultQueryLogLevel,
+				SlowQueryThreshold: DefaultSlowQueryThreshold,
+				QueryLogRedact:     DefaultQueryLogRedact,
+			},
+		},
+		{
+			scenario: "query logging",
+			input: struct {
+				envFile string
+				env     map[string]string
+				args    []string
+			}{
+				env: map[string]string{
+					"APP_MYSQL_DATABASE":             "db001",
+					"APP_MYSQL_USER":                 "user001",
+					"APP_MYSQL_QUERY_LOG_LEVEL":      "info",
+					"APP_MYSQL_SLOW_QUERY_THRESHOLD": "1s",
+				},
+				args: []string{"-mysql-query-log-redact=authors.bio,authors.name"},
+			},
+			expected: MySQL{
+				Database:       "db001",
+				User:           "user001",
+				Host:           DefaultHost,
+				Port:           DefaultPort,
+				Charset:        DefaultCharset,
+				ParseTime:      true,
+				ConnectTimeout: DefaultConnectTimeout,
+				ReadTimeout:    DefaultReadTimeout,
+				WriteTimeout:   DefaultWriteTimeout,
+
+				MaxOpenConns:    DefaultMaxOpenConns,
+				MaxIdleConns:    DefaultMaxIdleConns,
+				ConnMaxLifetime: DefaultConnMaxLifetime,
+				ConnMaxIdleTime: DefaultConnMaxIdleTime,
+				PingAttempts:    DefaultPingAttempts,
+				PingBackoff:     DefaultPingBackoff,
+
+				QueryLogLevel:      slog.LevelInfo,
+				SlowQueryThreshold: time.Second,
+				QueryLogRedact:     "authors.bio,authors.name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			envFile := filepath.Join(t.TempDir(), ".env")
+			err := os.WriteFile(envFile, []byte(tt.input.envFile), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.input.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, _, err := LoadWithFlags("APP_MYSQL_", envFile, tt.input.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cfg != tt.expected {
+				t.Errorf("got=%+v, want=%+v", cfg, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGetenv(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			envFile string
+			env     map[string]string
+		}
+		expected string
+	}{
+		{
+			scenario: "env file",
+			input: struct {
+				envFile string
+				env     map[string]string
+			}{
+				envFile: "APP_MYSQL_SERVER=memory\n",
+			},
+			expected: "memory",
+		},
+		{
+			scenario: "environment overrides env file",
+			input: struct {
+				envFile string
+				env     map[string]string
+			}{
+				envFile: "APP_MYSQL_SERVER=memory\n",
+				env:     map[string]string{"APP_MYSQL_SERVER": "docker"},
+			},
+			expected: "docker",
+		},
+		{
+			scenario: "unset",
+			input: struct {
+				envFile string
+				env     map[string]string
+			}{},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			envFile := filepath.Join(t.TempDir(), ".env")
+			err := os.WriteFile(envFile, []byte(tt.input.envFile), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.input.env {
+				t.Setenv(k, v)
+			}
+
+			got, err := Getenv("APP_MYSQL_SERVER", envFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.expected {
+				t.Errorf("got=%v, want=%v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    MySQL
+		expected bool
+	}{
+		{
+			scenario: "valid",
+			input: MySQL{
+				Database: "db001",
+				User:     "user001",
+				Host:     "localhost",
+				Port:     "3306",
+
+				PingAttempts: 1,
+			},
+			expected: true,
+		},
+		{
+			scenario: "missing database and user",
+			input: MySQL{
+				Host: "localhost",
+				Port: "3306",
+			},
+			expected: false,
+		},
+		{
+			scenario: "more idle than open connections",
+			input: MySQL{
+				Database: "db001",
+				User:     "user001",
+				Host:     "localhost",
+				Port:     "3306",
+
+				MaxOpenConns: 5,
+				MaxIdleConns: 10,
+				PingAttempts: 1,
+			},
+			expected: false,
+		},
+		{
+			scenario: "invalid port",
+			input: MySQL{
+				Database: "db001",
+				User:     "user001",
+				Host:     "localhost",
+				Port:     "mysql",
+			},
+			expected: false,
+		},
+		{
+			scenario: "negative slow query threshold",
+			input: MySQL{
+				Database: "db001",
+				User:     "user001",
+				Host:     "localhost",
+				Port:     "3306",
+
+				PingAttempts:       1,
+				SlowQueryThreshold: -time.Second,
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			err := tt.input.Validate()
+			if (err == nil) != tt.expected {
+				t.Errorf("got=%v, want valid=%v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDSN(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    MySQL
+		expected string
+	}{
+		{
+			scenario: "escape password and set options",
+			input: MySQL{
+				Database:       "db001",
+				User:           "user001",
+				Password:       "p@ss/w:rd?",
+				Host:           "localhost",
+				Port:           "3306",
+				Charset:        "utf8mb4",
+				ParseTime:      true,
+				ConnectTimeout: 10 * time.Second,
+				ReadTimeout:    30 * time.Second,
+				WriteTimeout:   30 * time.Second,
+			},
+			expected: "user001:p@ss/w:rd?@tcp(localhost:3306)/db001?clientFoundRows=true&parseTime=true&readTimeout=30s&timeout=10s&writeTimeout=30s&charset=utf8mb4&time_zone=%27%2B00%3A00%27",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			dsn, err := tt.input.DSN()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if dsn != tt.expected {
+				t.Errorf("got=%v, want=%v", dsn, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+// Package database opens the MySQL connection pool described by
+// config.MySQL and reports its health.
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/config"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// maxPingBackoff caps the delay between two startup health check attempts.
+const maxPingBackoff = 10 * time.Second
+
+// Open opens the database, applies the pool limits from cfg and pings it
+// until it answers or cfg.PingAttempts is exhausted. The returned *sql.DB
+// must be closed by the caller.
+func Open(ctx context.Context, cfg config.MySQL) (*sql.DB, error) {
+	dataSource, err := cfg.DSN()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("mysql", dataSource)
+	if err != nil {
+		return nil, err
+	}
+	ConfigurePool(db, cfg)
+
+	if err := Ping(ctx, db, cfg.PingAttempts, cfg.PingBackoff); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// ConfigurePool applies the connection pool limits from cfg to db.
+func ConfigurePool(db *sql.DB, cfg config.MySQL) {
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+}
+
+// Ping pings db up to attempts times. The delay between attempts starts at
+// backoff and doubles after every failure.
+func Ping(ctx context.Context, db *sql.DB, attempts int, backoff time.Duration) error {
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		log.Printf("ping database (attempt %d/%d): %s", attempt, attempts, err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("ping database: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxPingBackoff)
+	}
+	return fmt.Errorf("ping database after %d attempts: %w", attempts, err)
+}
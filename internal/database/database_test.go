@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/config"
+)
+
+func TestPing(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			ctx      func() context.Context
+			attempts int
+		}
+		expected error
+	}{
+		{
+			scenario: "give up after attempts",
+			input: struct {
+				ctx      func() context.Context
+				attempts int
+			}{
+				ctx:      context.Background,
+				attempts: 3,
+			},
+			expected: nil,
+		},
+		{
+			scenario: "stop when context is canceled",
+			input: struct {
+				ctx      func() context.Context
+				attempts int
+			}{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					return ctx
+				},
+				attempts: 3,
+			},
+			expected: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			// nothing listens on port 1
+			cfg := config.Default()
+			cfg.Database = "db001"
+			cfg.User = "user001"
+			cfg.Host = "127.0.0.1"
+			cfg.Port = "1"
+			cfg.ConnectTimeout = 100 * time.Millisecond
+			dataSource, err := cfg.DSN()
+			if err != nil {
+				t.Fatal(err)
+			}
+			db, err := sql.Open("mysql", dataSource)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				db.Close()
+			})
+
+			err = Ping(tt.input.ctx(), db, tt.input.attempts, time.Millisecond)
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfigurePool(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    config.MySQL
+		expected int
+	}{
+		{
+			scenario: "apply max open conns",
+			input: config.MySQL{
+				MaxOpenConns: 7,
+				MaxIdleConns: 3,
+			},
+			expected: 7,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			db, err := sql.Open("mysql", "user001@tcp(127.0.0.1:1)/db001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				db.Close()
+			})
+
+			ConfigurePool(db, tt.input)
+
+			stats := StatsOf(db)
+			if stats.MaxOpenConnections != tt.expected {
+				t.Errorf("got=%v, want=%v", stats.MaxOpenConnections, tt.expected)
+			}
+		})
+	}
+}
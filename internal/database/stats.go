@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Stats is a JSON-friendly snapshot of sql.DBStats.
+type Stats struct {
+	MaxOpenConnections int `json:"max_open_connections"`
+
+	OpenConnections int `json:"open_connections"`
+	InUse           int `json:"in_use"`
+	Idle            int `json:"idle"`
+
+	WaitCount         int64  `json:"wait_count"`
+	WaitDuration      string `json:"wait_duration"`
+	MaxIdleClosed     int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed int64  `json:"max_lifetime_closed"`
+}
+
+// StatsOf returns the current pool statistics of db.
+func StatsOf(db *sql.DB) Stats {
+	s := db.Stats()
+	return Stats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDuration:       s.WaitDuration.String(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf(
+		"open=%d/%d in_use=%d idle=%d wait_count=%d wait_duration=%s closed(max_idle=%d max_idle_time=%d max_lifetime=%d)",
+		s.OpenConnections, s.MaxOpenConnections, s.InUse, s.Idle,
+		s.WaitCount, s.WaitDuration,
+		s.MaxIdleClosed, s.MaxIdleTimeClosed, s.MaxLifetimeClosed,
+	)
+}
@@ -0,0 +1,89 @@
+// Package dberr maps errors returned by database/sql and the MySQL driver
+// to a small set of domain errors that callers can test with errors.Is.
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+var (
+	// ErrNotFound reports that the requested row does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists reports a duplicate primary or unique key.
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrReferenceMissing reports a foreign key pointing at a missing row,
+	// e.g. a book whose publisher_uuid does not exist.
+	ErrReferenceMissing = errors.New("referenced row does not exist")
+	// ErrInUse reports a row that cannot be deleted or changed because
+	// other rows reference it, e.g. a publisher that still has books.
+	ErrInUse = errors.New("row is still referenced")
+	// ErrConflict reports an update based on a stale version of a row,
+	// i.e. the row was changed by someone else since it was read.
+	ErrConflict = errors.New("version conflict")
+)
+
+// MySQL server error codes, see
+// https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	codeDupEntry            = 1062
+	codeNoReferencedRow     = 1216
+	codeRowIsReferenced     = 1217
+	codeRowIsReferenced2    = 1451
+	codeNoReferencedRow2    = 1452
+	codeDupEntryWithKeyName = 1586
+)
+
+// Translate wraps err with the matching domain error. The original error is
+// kept in the chain, so both errors.Is(err, ErrNotFound) and
+// errors.Is(err, sql.ErrNoRows) hold. Errors without a domain meaning are
+// returned unchanged.
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return wrap(ErrNotFound, err)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return err
+	}
+	switch mysqlErr.Number {
+	case codeDupEntry, codeDupEntryWithKeyName:
+		return wrap(ErrAlreadyExists, err)
+	case codeNoReferencedRow, codeNoReferencedRow2:
+		return wrap(ErrReferenceMissing, err)
+	case codeRowIsReferenced, codeRowIsReferenced2:
+		return wrap(ErrInUse, err)
+	}
+	return err
+}
+
+func wrap(kind error, err error) error {
+	if errors.Is(err, kind) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", kind, err)
+}
+
+// CheckAffected wraps the result of an :execrows query. It translates err
+// and reports ErrNotFound when the statement matched no row.
+//
+// UPDATE statements only count matched rows when the connection sets
+// CLIENT_FOUND_ROWS; config.MySQL always enables it, otherwise an update
+// that leaves a row unchanged would be reported as not found.
+func CheckAffected(n int64, err error) error {
+	if err != nil {
+		return Translate(err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
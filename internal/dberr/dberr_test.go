@@ -0,0 +1,133 @@
+package dberr
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    error
+		expected error
+	}{
+		{
+			scenario: "no rows",
+			input:    sql.ErrNoRows,
+			expected: ErrNotFound,
+		},
+		{
+			scenario: "duplicate uuid",
+			input:    &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'x' for key 'authors.PRIMARY'"},
+			expected: ErrAlreadyExists,
+		},
+		{
+			scenario: "missing publisher",
+			input:    fmt.Errorf("create book: %w", &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}),
+			expected: ErrReferenceMissing,
+		},
+		{
+			scenario: "publisher still has books",
+			input:    &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"},
+			expected: ErrInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			err := Translate(tt.input)
+
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+			if !errors.Is(err, tt.input) {
+				t.Errorf("got=%v, want wrapped %v", err, tt.input)
+			}
+		})
+	}
+}
+
+func TestTranslateUnknown(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    error
+	}{
+		{
+			scenario: "nil",
+			input:    nil,
+		},
+		{
+			scenario: "other mysql error",
+			input:    &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			err := Translate(tt.input)
+
+			if err != tt.input {
+				t.Errorf("got=%v, want=%v", err, tt.input)
+			}
+		})
+	}
+}
+
+func TestCheckAffected(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			n   int64
+			err error
+		}
+		expected error
+	}{
+		{
+			scenario: "row affected",
+			input: struct {
+				n   int64
+				err error
+			}{
+				n: 1,
+			},
+			expected: nil,
+		},
+		{
+			scenario: "no row affected",
+			input: struct {
+				n   int64
+				err error
+			}{
+				n: 0,
+			},
+			expected: ErrNotFound,
+		},
+		{
+			scenario: "query failed",
+			input: struct {
+				n   int64
+				err error
+			}{
+				err: &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"},
+			},
+			expected: ErrInUse,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			err := CheckAffected(tt.input.n, tt.input.err)
+
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
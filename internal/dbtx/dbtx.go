@@ -0,0 +1,223 @@
+// Package dbtx wraps a *sql.DB or *sql.Tx in a chain of interceptors that
+// see every statement passed to it, e.g. by the sqlc queries:
+//
+//	db := dbtx.Wrap(sqlDB, dbtx.Observe(func(ctx context.Context, q dbtx.Query, r dbtx.Result, d time.Duration) {
+//		log.Printf("%s took %s: %v", q.Name, d, r.Err)
+//	}))
+//	queries := sqlc.New(db)
+//
+// Interceptors see the method, the sqlc query name, the SQL text and the
+// arguments, and may change them, change the result or not run the
+// statement at all. Transactions begun on a wrapped DB use the same chain.
+// Statements prepared with PrepareContext run without it.
+package dbtx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// Method is the DBTX method a statement was passed to.
+type Method string
+
+const (
+	MethodExec     Method = "Exec"
+	MethodQuery    Method = "Query"
+	MethodQueryRow Method = "QueryRow"
+	MethodPrepare  Method = "Prepare"
+)
+
+// Query is a statement passed to a wrapped DB or Tx.
+type Query struct {
+	Method Method
+	// Name is the sqlc query name from the "-- name: GetAuthor :one"
+	// header of SQL, or empty for statements without one.
+	Name string
+	SQL  string
+	Args []any
+}
+
+// Result is the outcome of a Query. Only the field of the query's method
+// is set, besides Err.
+type Result struct {
+	Exec sql.Result
+	Rows *sql.Rows
+	// Row is the result of QueryRow. database/sql offers no other way to
+	// make one, so interceptors must return the row of next; to fail the
+	// query, call next with a cancelled context.
+	Row  *sql.Row
+	Stmt *sql.Stmt
+	// Err is the error of the statement. For QueryRow it does not include
+	// sql.ErrNoRows, which only Scan reports.
+	Err error
+}
+
+// Handler runs a query.
+type Handler func(ctx context.Context, q Query) Result
+
+// Interceptor runs a query by calling next.
+type Interceptor func(ctx context.Context, q Query, next Handler) Result
+
+// Chain returns a handler that passes queries through interceptors, the
+// first one outermost, to h.
+func Chain(h Handler, interceptors ...Interceptor) Handler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], h
+		h = func(ctx context.Context, q Query) Result {
+			return interceptor(ctx, q, next)
+		}
+	}
+	return h
+}
+
+// Observe returns an interceptor that calls fn after every query with its
+// result and how long it took.
+func Observe(fn func(ctx context.Context, q Query, r Result, d time.Duration)) Interceptor {
+	return func(ctx context.Context, q Query, next Handler) Result {
+		start := time.Now()
+		r := next(ctx, q)
+		fn(ctx, q, r, time.Since(start))
+		return r
+	}
+}
+
+// DB is a *sql.DB whose statements pass through interceptors.
+type DB struct {
+	statements
+	db           *sql.DB
+	interceptors []Interceptor
+}
+
+var _ sqlc.DBTX = (*DB)(nil)
+
+// Wrap returns db with interceptors, the first one outermost.
+func Wrap(db *sql.DB, interceptors ...Interceptor) *DB {
+	return &DB{
+		statements:   statements{Chain(run(db), interceptors...)},
+		db:           db,
+		interceptors: interceptors,
+	}
+}
+
+// BeginTx starts a transaction with the interceptors of db.
+func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	tx, err := db.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return WrapTx(tx, db.interceptors...), nil
+}
+
+// PingContext pings the database.
+func (db *DB) PingContext(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
+// Unwrap returns the *sql.DB without interceptors.
+func (db *DB) Unwrap() *sql.DB {
+	return db.db
+}
+
+// Tx is a *sql.Tx whose statements pass through interceptors.
+type Tx struct {
+	statements
+	tx *sql.Tx
+}
+
+var _ sqlc.DBTX = (*Tx)(nil)
+
+// WrapTx returns tx with interceptors, the first one outermost.
+func WrapTx(tx *sql.Tx, interceptors ...Interceptor) *Tx {
+	return &Tx{
+		statements: statements{Chain(run(tx), interceptors...)},
+		tx:         tx,
+	}
+}
+
+// Commit commits the transaction.
+func (tx *Tx) Commit() error {
+	return tx.tx.Commit()
+}
+
+// Rollback aborts the transaction.
+func (tx *Tx) Rollback() error {
+	return tx.tx.Rollback()
+}
+
+// Unwrap returns the *sql.Tx without interceptors.
+func (tx *Tx) Unwrap() *sql.Tx {
+	return tx.tx
+}
+
+// statements implements sqlc.DBTX by passing every statement to handler.
+type statements struct {
+	handler Handler
+}
+
+func (s statements) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	r := s.handler(ctx, newQuery(MethodExec, query, args))
+	return r.Exec, r.Err
+}
+
+func (s statements) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	r := s.handler(ctx, newQuery(MethodPrepare, query, nil))
+	return r.Stmt, r.Err
+}
+
+func (s statements) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	r := s.handler(ctx, newQuery(MethodQuery, query, args))
+	return r.Rows, r.Err
+}
+
+func (s statements) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	r := s.handler(ctx, newQuery(MethodQueryRow, query, args))
+	if r.Row == nil {
+		panic("dbtx: an interceptor returned no row for QueryRow")
+	}
+	return r.Row
+}
+
+func newQuery(method Method, query string, args []any) Query {
+	return Query{Method: method, Name: queryName(query), SQL: query, Args: args}
+}
+
+// run returns the handler that passes queries to db.
+func run(db sqlc.DBTX) Handler {
+	return func(ctx context.Context, q Query) Result {
+		switch q.Method {
+		case MethodExec:
+			result, err := db.ExecContext(ctx, q.SQL, q.Args...)
+			return Result{Exec: result, Err: err}
+		case MethodQuery:
+			rows, err := db.QueryContext(ctx, q.SQL, q.Args...)
+			return Result{Rows: rows, Err: err}
+		case MethodQueryRow:
+			row := db.QueryRowContext(ctx, q.SQL, q.Args...)
+			return Result{Row: row, Err: row.Err()}
+		case MethodPrepare:
+			stmt, err := db.PrepareContext(ctx, q.SQL)
+			return Result{Stmt: stmt, Err: err}
+		}
+		return Result{Err: fmt.Errorf("dbtx: unknown method %q", q.Method)}
+	}
+}
+
+// queryName returns the name in the "-- name: GetAuthor :one" header that
+// sqlc puts in front of its queries, or "" if query has none.
+func queryName(query string) string {
+	header, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return ""
+	}
+	header, _, _ = strings.Cut(header, "\n")
+	fields := strings.Fields(header)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
@@ -0,0 +1,117 @@
+package dbtx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    string
+		expected string
+	}{
+		{
+			scenario: "sqlc query",
+			input:    "-- name: GetAuthor :one\nSELECT * FROM authors WHERE uuid = ?",
+			expected: "GetAuthor",
+		},
+		{
+			scenario: "header only",
+			input:    "-- name: PurgeAuthors :execrows",
+			expected: "PurgeAuthors",
+		},
+		{
+			scenario: "plain statement",
+			input:    "SAVEPOINT catalog_savepoint_1",
+			expected: "",
+		},
+		{
+			scenario: "other comment",
+			input:    "-- lists authors\nSELECT * FROM authors",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			got := queryName(tt.input)
+			if got != tt.expected {
+				t.Errorf("got=%q, want=%q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChain(t *testing.T) {
+	errInjected := errors.New("injected")
+
+	var calls []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, q Query, next Handler) Result {
+			calls = append(calls, name+" "+q.Name)
+			return next(ctx, q)
+		}
+	}
+	fail := func(ctx context.Context, q Query, next Handler) Result {
+		if q.Name == "DeleteAuthor" {
+			return Result{Err: errInjected}
+		}
+		return next(ctx, q)
+	}
+	handler := func(ctx context.Context, q Query) Result {
+		calls = append(calls, "handler "+q.Name)
+		return Result{}
+	}
+
+	tests := []struct {
+		scenario string
+		input    Query
+		expected struct {
+			calls []string
+			err   error
+		}
+	}{
+		{
+			scenario: "outermost first",
+			input:    newQuery(MethodExec, "-- name: CreateAuthor :exec\nINSERT INTO authors", nil),
+			expected: struct {
+				calls []string
+				err   error
+			}{
+				calls: []string{"outer CreateAuthor", "inner CreateAuthor", "handler CreateAuthor"},
+			},
+		},
+		{
+			scenario: "interceptor fails query",
+			input:    newQuery(MethodExec, "-- name: DeleteAuthor :execrows\nUPDATE authors", nil),
+			expected: struct {
+				calls []string
+				err   error
+			}{
+				calls: []string{"outer DeleteAuthor"},
+				err:   errInjected,
+			},
+		},
+	}
+
+	chain := Chain(handler, record("outer"), fail, record("inner"))
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			calls = nil
+
+			r := chain(context.Background(), tt.input)
+			if !errors.Is(r.Err, tt.expected.err) {
+				t.Errorf("got=%v, want=%v", r.Err, tt.expected.err)
+			}
+			if !reflect.DeepEqual(calls, tt.expected.calls) {
+				t.Errorf("got=%v, want=%v", calls, tt.expected.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package export streams the live rows of the catalog to NDJSON, JSON or
+// CSV.
+//
+// Rows are read in keyset pages and written as they arrive, so memory use
+// depends on the page size rather than on the size of the catalog. Run the
+// export against a read-only transaction to get a consistent snapshot
+// across pages.
+package export
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// Kind selects the rows to export.
+type Kind string
+
+const (
+	KindAuthors     Kind = "authors"
+	KindPublishers  Kind = "publishers"
+	KindBooks       Kind = "books"
+	KindAuthorBooks Kind = "author_books"
+)
+
+type Options struct {
+	Format Format
+	// Denormalize adds publisher_name and author_names to books and
+	// author_name and book_title to author-book links.
+	Denormalize bool
+	// PageSize is the number of rows read per query. Zero selects
+	// pagination.MaxLimit.
+	PageSize int
+}
+
+// Export writes every live row of kind to w and returns the number of rows
+// written.
+func Export(ctx context.Context, svc *catalog.Service, w io.Writer, kind Kind, opts Options) (int, error) {
+	switch kind {
+	case KindAuthors:
+		return export(ctx, w, opts, authorsTable(svc))
+	case KindPublishers:
+		return export(ctx, w, opts, publishersTable(svc))
+	case KindBooks:
+		if opts.Denormalize {
+			return export(ctx, w, opts, bookSummariesTable(svc))
+		}
+		return export(ctx, w, opts, booksTable(svc))
+	case KindAuthorBooks:
+		return export(ctx, w, opts, authorBooksTable(svc, opts.Denormalize))
+	}
+	return 0, fmt.Errorf("unknown kind %q", kind)
+}
+
+// table describes the export of one kind of row.
+type table[T any] struct {
+	columns []string
+	list    func(ctx context.Context, req pagination.Request) (pagination.Page[T], error)
+	// values returns the values of a row in column order; see writeRow for
+	// the supported types.
+	values func(T) []any
+}
+
+func export[T any](ctx context.Context, w io.Writer, opts Options, t table[T]) (int, error) {
+	rw, err := newRowWriter(w, opts.Format, t.columns)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	req := pagination.Request{Limit: opts.PageSize}
+	if req.Limit == 0 {
+		req.Limit = pagination.MaxLimit
+	}
+	for {
+		page, err := t.list(ctx, req)
+		if err != nil {
+			return n, err
+		}
+		for _, row := range page.Items {
+			if err := rw.writeRow(t.values(row)); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		req.Cursor = page.NextCursor
+	}
+	return n, rw.close()
+}
+
+func listAll[T any](list func(context.Context, pagination.Request, catalog.ListFilter) (pagination.Page[T], error)) func(context.Context, pagination.Request) (pagination.Page[T], error) {
+	return func(ctx context.Context, req pagination.Request) (pagination.Page[T], error) {
+		return list(ctx, req, catalog.ListFilter{})
+	}
+}
+
+func authorsTable(svc *catalog.Service) table[sqlc.Author] {
+	return table[sqlc.Author]{
+		columns: []string{"uuid", "name", "bio", "version", "created_at", "updated_at"},
+		list:    listAll(svc.ListAuthors),
+		values: func(a sqlc.Author) []any {
+			return []any{a.Uuid, a.Name, nullString(a.Bio), a.Version, a.CreatedAt, a.UpdatedAt}
+		},
+	}
+}
+
+func publishersTable(svc *catalog.Service) table[sqlc.Publisher] {
+	return table[sqlc.Publisher]{
+		columns: []string{"uuid", "name", "version", "created_at", "updated_at"},
+		list:    listAll(svc.ListPublishers),
+		values: func(p sqlc.Publisher) []any {
+			return []any{p.Uuid, p.Name, p.Version, p.CreatedAt, p.UpdatedAt}
+		},
+	}
+}
+
+func booksTable(svc *catalog.Service) table[sqlc.Book] {
+	return table[sqlc.Book]{
+		columns: []string{"uuid", "title", "publisher_uuid", "version", "created_at", "updated_at"},
+		list:    listAll(svc.ListBooks),
+		values: func(b sqlc.Book) []any {
+			return []any{b.Uuid, b.Title, b.PublisherUuid, b.Version, b.CreatedAt, b.UpdatedAt}
+		},
+	}
+}
+
+func bookSummariesTable(svc *catalog.Service) table[catalog.BookSummary] {
+	return table[catalog.BookSummary]{
+		columns: []string{"uuid", "title", "publisher_uuid", "version", "created_at", "updated_at", "publisher_name", "author_names"},
+		list:    svc.ListBookSummaries,
+		values: func(b catalog.BookSummary) []any {
+			names := b.AuthorNames
+			if names == nil {
+				names = []string{}
+			}
+			return []any{b.Uuid, b.Title, b.PublisherUuid, b.Version, b.CreatedAt, b.UpdatedAt, b.PublisherName, names}
+		},
+	}
+}
+
+func authorBooksTable(svc *catalog.Service, denormalize bool) table[sqlc.ListAuthorBooksPageRow] {
+	t := table[sqlc.ListAuthorBooksPageRow]{
+		columns: []string{"author_uuid", "book_uuid"},
+		list:    listAll(svc.ListAuthorBooks),
+		values: func(ab sqlc.ListAuthorBooksPageRow) []any {
+			return []any{ab.AuthorUuid, ab.BookUuid}
+		},
+	}
+	if denormalize {
+		t.columns = append(t.columns, "author_name", "book_title")
+		t.values = func(ab sqlc.ListAuthorBooksPageRow) []any {
+			return []any{ab.AuthorUuid, ab.BookUuid, ab.AuthorName, ab.BookTitle}
+		}
+	}
+	return t
+}
+
+func nullString(s sql.NullString) any {
+	if !s.Valid {
+		return nil
+	}
+	return s.String
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type Format string
+
+const (
+	// FormatNDJSON writes one JSON object per line.
+	FormatNDJSON Format = "ndjson"
+	// FormatJSON writes a JSON array of objects.
+	FormatJSON Format = "json"
+	// FormatCSV writes a header row followed by one row per record. Lists,
+	// e.g. author_names, are written as JSON arrays.
+	FormatCSV Format = "csv"
+)
+
+// rowWriter writes the rows of one export. Values are nil, strings,
+// unsigned integers, []string or encoding.TextMarshalers such as
+// binuuid.UUID and time.Time.
+type rowWriter interface {
+	writeRow(values []any) error
+	// close finishes the document; it does not close the underlying writer.
+	close() error
+}
+
+func newRowWriter(w io.Writer, format Format, columns []string) (rowWriter, error) {
+	switch format {
+	case FormatNDJSON, FormatJSON:
+		return &jsonWriter{w: w, columns: columns, array: format == FormatJSON}, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw, record: make([]string, len(columns))}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q", format)
+}
+
+type jsonWriter struct {
+	w       io.Writer
+	columns []string
+	// array wraps the objects in a JSON array.
+	array bool
+	rows  int
+	buf   bytes.Buffer
+}
+
+func (jw *jsonWriter) writeRow(values []any) error {
+	jw.buf.Reset()
+	switch {
+	case !jw.array:
+	case jw.rows == 0:
+		jw.buf.WriteString("[\n")
+	default:
+		jw.buf.WriteString(",\n")
+	}
+
+	// Write the object by hand to keep the columns in order.
+	jw.buf.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			jw.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(jw.columns[i])
+		jw.buf.Write(key)
+		jw.buf.WriteByte(':')
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", jw.columns[i], err)
+		}
+		jw.buf.Write(data)
+	}
+	jw.buf.WriteByte('}')
+	if !jw.array {
+		jw.buf.WriteByte('\n')
+	}
+
+	jw.rows++
+	_, err := jw.w.Write(jw.buf.Bytes())
+	return err
+}
+
+func (jw *jsonWriter) close() error {
+	if !jw.array {
+		return nil
+	}
+	end := "\n]\n"
+	if jw.rows == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(jw.w, end)
+	return err
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	record []string
+}
+
+func (cw *csvWriter) writeRow(values []any) error {
+	for i, value := range values {
+		field, err := csvField(value)
+		if err != nil {
+			return err
+		}
+		cw.record[i] = field
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+func csvField(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), nil
+	case []string:
+		data, err := json.Marshal(v)
+		return string(data), err
+	case encoding.TextMarshaler:
+		data, err := v.MarshalText()
+		return string(data), err
+	}
+	return "", fmt.Errorf("unsupported value %T", value)
+}
@@ -0,0 +1,117 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+func TestRowWriter(t *testing.T) {
+	columns := []string{"uuid", "name", "bio", "version", "created_at", "author_names"}
+	rows := [][]any{
+		{
+			binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+			"Rob Pike",
+			nil,
+			uint32(1),
+			time.Date(2024, time.January, 2, 3, 4, 5, 600000000, time.UTC),
+			[]string{"a", "b, c"},
+		},
+		{
+			binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b11"),
+			"Ken \"ken\" Thompson",
+			"Unix",
+			uint32(2),
+			time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+			[]string{},
+		},
+	}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			format Format
+			rows   [][]any
+		}
+		expected string
+	}{
+		{
+			scenario: "ndjson",
+			input: struct {
+				format Format
+				rows   [][]any
+			}{
+				format: FormatNDJSON,
+				rows:   rows,
+			},
+			expected: `{"uuid":"0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10","name":"Rob Pike","bio":null,"version":1,"created_at":"2024-01-02T03:04:05.6Z","author_names":["a","b, c"]}
+{"uuid":"0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b11","name":"Ken \"ken\" Thompson","bio":"Unix","version":2,"created_at":"2024-01-02T03:04:05Z","author_names":[]}
+`,
+		},
+		{
+			scenario: "json",
+			input: struct {
+				format Format
+				rows   [][]any
+			}{
+				format: FormatJSON,
+				rows:   rows,
+			},
+			expected: `[
+{"uuid":"0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10","name":"Rob Pike","bio":null,"version":1,"created_at":"2024-01-02T03:04:05.6Z","author_names":["a","b, c"]},
+{"uuid":"0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b11","name":"Ken \"ken\" Thompson","bio":"Unix","version":2,"created_at":"2024-01-02T03:04:05Z","author_names":[]}
+]
+`,
+		},
+		{
+			scenario: "empty json",
+			input: struct {
+				format Format
+				rows   [][]any
+			}{
+				format: FormatJSON,
+				rows:   nil,
+			},
+			expected: "[]\n",
+		},
+		{
+			scenario: "csv",
+			input: struct {
+				format Format
+				rows   [][]any
+			}{
+				format: FormatCSV,
+				rows:   rows,
+			},
+			expected: `uuid,name,bio,version,created_at,author_names
+0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10,Rob Pike,,1,2024-01-02T03:04:05.6Z,"[""a"",""b, c""]"
+0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b11,"Ken ""ken"" Thompson",Unix,2,2024-01-02T03:04:05Z,[]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			var b strings.Builder
+			rw, err := newRowWriter(&b, tt.input.format, columns)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range tt.input.rows {
+				if err := rw.writeRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := rw.close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if b.String() != tt.expected {
+				t.Errorf("got=%v, want=%v", b.String(), tt.expected)
+			}
+		})
+	}
+}
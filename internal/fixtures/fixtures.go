@@ -0,0 +1,149 @@
+// Package fixtures creates valid catalog rows for tests and sample data.
+//
+// A Factory inserts authors, publishers, books and author-book links with
+// default values, which overrides may change before the row is written:
+//
+//	f := fixtures.New(sqlc.New(db))
+//	book, err := f.Book(ctx, func(p *sqlc.CreateBookParams) {
+//		p.Title = "The Go Programming Language"
+//	})
+//
+// Rows that the new row references are created as well unless an override
+// names them, so a book without a publisher gets a publisher of its own.
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// Factory creates rows with q. It is safe for concurrent use.
+type Factory struct {
+	q sqlc.Querier
+
+	mu sync.Mutex
+	// rand generates the uuids; nil uses random ones.
+	rand *rand.Rand
+	// seq counts the rows created of every kind, which numbers the
+	// default names.
+	seq map[string]int
+}
+
+// New returns a factory that gives rows random uuids.
+func New(q sqlc.Querier) *Factory {
+	return &Factory{q: q, seq: make(map[string]int)}
+}
+
+// NewSeeded returns a factory whose uuids depend only on seed and the order
+// in which rows are created.
+func NewSeeded(q sqlc.Querier, seed int64) *Factory {
+	f := New(q)
+	f.rand = rand.New(rand.NewSource(seed))
+	return f
+}
+
+// Author creates an author named "authorNNN" with a bio.
+func (f *Factory) Author(ctx context.Context, overrides ...func(*sqlc.CreateAuthorParams)) (sqlc.CreateAuthorParams, error) {
+	uuid, n := f.next("author")
+	p := sqlc.CreateAuthorParams{
+		Uuid: uuid,
+		Name: fmt.Sprintf("author%03d", n),
+		Bio:  sql.NullString{String: fmt.Sprintf("bio of author%03d", n), Valid: true},
+	}
+	for _, override := range overrides {
+		override(&p)
+	}
+	if err := f.q.CreateAuthor(ctx, p); err != nil {
+		return p, fmt.Errorf("create author %s: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+// Publisher creates a publisher named "publisherNNN".
+func (f *Factory) Publisher(ctx context.Context, overrides ...func(*sqlc.CreatePublisherParams)) (sqlc.CreatePublisherParams, error) {
+	uuid, n := f.next("publisher")
+	p := sqlc.CreatePublisherParams{
+		Uuid: uuid,
+		Name: fmt.Sprintf("publisher%03d", n),
+	}
+	for _, override := range overrides {
+		override(&p)
+	}
+	if err := f.q.CreatePublisher(ctx, p); err != nil {
+		return p, fmt.Errorf("create publisher %s: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+// Book creates a book titled "bookNNN", with a new publisher unless an
+// override sets PublisherUuid.
+func (f *Factory) Book(ctx context.Context, overrides ...func(*sqlc.CreateBookParams)) (sqlc.CreateBookParams, error) {
+	uuid, n := f.next("book")
+	p := sqlc.CreateBookParams{
+		Uuid:  uuid,
+		Title: fmt.Sprintf("book%03d", n),
+	}
+	for _, override := range overrides {
+		override(&p)
+	}
+	if p.PublisherUuid == binuuid.Nil {
+		publisher, err := f.Publisher(ctx)
+		if err != nil {
+			return p, err
+		}
+		p.PublisherUuid = publisher.Uuid
+	}
+	if err := f.q.CreateBook(ctx, p); err != nil {
+		return p, fmt.Errorf("create book %s: %w", p.Title, err)
+	}
+	return p, nil
+}
+
+// AuthorBook links an author to a book, creating either unless an override
+// sets AuthorUuid or BookUuid.
+func (f *Factory) AuthorBook(ctx context.Context, overrides ...func(*sqlc.CreateAuthorBookParams)) (sqlc.CreateAuthorBookParams, error) {
+	var p sqlc.CreateAuthorBookParams
+	for _, override := range overrides {
+		override(&p)
+	}
+	if p.AuthorUuid == binuuid.Nil {
+		author, err := f.Author(ctx)
+		if err != nil {
+			return p, err
+		}
+		p.AuthorUuid = author.Uuid
+	}
+	if p.BookUuid == binuuid.Nil {
+		book, err := f.Book(ctx)
+		if err != nil {
+			return p, err
+		}
+		p.BookUuid = book.Uuid
+	}
+	if err := f.q.CreateAuthorBook(ctx, p); err != nil {
+		return p, fmt.Errorf("link author %s to book %s: %w", p.AuthorUuid, p.BookUuid, err)
+	}
+	return p, nil
+}
+
+// next returns the uuid and the number of the next row of kind.
+func (f *Factory) next(kind string) (binuuid.UUID, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.seq[kind]++
+	if f.rand == nil {
+		return binuuid.New(), f.seq[kind]
+	}
+	// Reading from a math/rand.Rand never fails.
+	u, _ := uuid.NewRandomFromReader(f.rand)
+	return binuuid.UUID(u), f.seq[kind]
+}
@@ -0,0 +1,43 @@
+package fixtures
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/memdb"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func TestSeed(t *testing.T) {
+	seed := func(t *testing.T, seed int64) (SeedResult, []sqlc.ListAuthorBooksRow) {
+		db := memdb.New()
+		result, err := Seed(context.Background(), db, seed, DefaultCatalog)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rows, err := db.ListAuthorBooks(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result, rows
+	}
+
+	result, rows := seed(t, 1)
+	if result.Publishers != DefaultCatalog.Publishers || result.Authors != DefaultCatalog.Authors || result.Books != DefaultCatalog.Books {
+		t.Errorf("got=%+v, want=%+v", result, DefaultCatalog)
+	}
+	if result.AuthorBooks < result.Books || result.AuthorBooks > result.Books*DefaultCatalog.AuthorsPerBook || len(rows) != result.AuthorBooks {
+		t.Errorf("got=%d links (%d rows) for %d books", result.AuthorBooks, len(rows), result.Books)
+	}
+
+	_, again := seed(t, 1)
+	if !reflect.DeepEqual(again, rows) {
+		t.Errorf("got=%v, want=%v", again, rows)
+	}
+
+	_, other := seed(t, 2)
+	if reflect.DeepEqual(other, rows) {
+		t.Errorf("got the same catalog for seeds 1 and 2")
+	}
+}
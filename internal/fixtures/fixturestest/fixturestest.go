@@ -0,0 +1,70 @@
+// Package fixturestest provides a fixtures.Factory for tests that fails the
+// test instead of returning errors:
+//
+//	f := fixturestest.New(t, queries)
+//	link := f.AuthorBook()
+//
+// It lives apart from package fixtures so that the seed command does not
+// link package testing.
+package fixturestest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/fixtures"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// Factory is a fixtures.Factory that stops the test when a row cannot be
+// created.
+type Factory struct {
+	t testing.TB
+	f *fixtures.Factory
+}
+
+// New returns a factory that creates rows with q and random uuids, and
+// stops t when that fails.
+func New(t testing.TB, q sqlc.Querier) *Factory {
+	return &Factory{t: t, f: fixtures.New(q)}
+}
+
+// Author is fixtures.Factory.Author.
+func (f *Factory) Author(overrides ...func(*sqlc.CreateAuthorParams)) sqlc.CreateAuthorParams {
+	f.t.Helper()
+	p, err := f.f.Author(context.Background(), overrides...)
+	f.check(err)
+	return p
+}
+
+// Publisher is fixtures.Factory.Publisher.
+func (f *Factory) Publisher(overrides ...func(*sqlc.CreatePublisherParams)) sqlc.CreatePublisherParams {
+	f.t.Helper()
+	p, err := f.f.Publisher(context.Background(), overrides...)
+	f.check(err)
+	return p
+}
+
+// Book is fixtures.Factory.Book.
+func (f *Factory) Book(overrides ...func(*sqlc.CreateBookParams)) sqlc.CreateBookParams {
+	f.t.Helper()
+	p, err := f.f.Book(context.Background(), overrides...)
+	f.check(err)
+	return p
+}
+
+// AuthorBook is fixtures.Factory.AuthorBook.
+func (f *Factory) AuthorBook(overrides ...func(*sqlc.CreateAuthorBookParams)) sqlc.CreateAuthorBookParams {
+	f.t.Helper()
+	p, err := f.f.AuthorBook(context.Background(), overrides...)
+	f.check(err)
+	return p
+}
+
+// check stops the test if err is not nil.
+func (f *Factory) check(err error) {
+	f.t.Helper()
+	if err != nil {
+		f.t.Fatalf("fixtures: %s", err)
+	}
+}
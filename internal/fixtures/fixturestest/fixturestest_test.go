@@ -0,0 +1,76 @@
+package fixturestest
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/memdb"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func TestFactory(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    func(f *Factory)
+		expected []sqlc.ListAuthorBooksRow
+	}{
+		{
+			scenario: "link with defaults",
+			input: func(f *Factory) {
+				f.AuthorBook()
+			},
+			expected: []sqlc.ListAuthorBooksRow{
+				{
+					AuthorName: "author001",
+					AuthorBio:  sql.NullString{String: "bio of author001", Valid: true},
+					BookTitle:  "book001",
+				},
+			},
+		},
+		{
+			scenario: "link with overrides",
+			input: func(f *Factory) {
+				author := f.Author(func(p *sqlc.CreateAuthorParams) {
+					p.Name = "Rob Pike"
+					p.Bio = sql.NullString{}
+				})
+				for i := 0; i < 2; i++ {
+					book := f.Book()
+					f.AuthorBook(func(p *sqlc.CreateAuthorBookParams) {
+						p.AuthorUuid = author.Uuid
+						p.BookUuid = book.Uuid
+					})
+				}
+			},
+			expected: []sqlc.ListAuthorBooksRow{
+				{AuthorName: "Rob Pike", BookTitle: "book001"},
+				{AuthorName: "Rob Pike", BookTitle: "book002"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			db := memdb.New()
+			tt.input(New(t, db))
+
+			rows, err := db.ListAuthorBooks(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The uuids are random, so compare the rest in title order.
+			for i := range rows {
+				rows[i].AuthorUuid, rows[i].BookUuid = binuuid.Nil, binuuid.Nil
+			}
+			sort.Slice(rows, func(i, j int) bool { return rows[i].BookTitle < rows[j].BookTitle })
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("got=%v, want=%v", rows, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package fixtures
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// Catalog is the size of a sample catalog.
+type Catalog struct {
+	Publishers int
+	Authors    int
+	Books      int
+	// AuthorsPerBook is the most authors a book is linked to; every book
+	// has at least one.
+	AuthorsPerBook int
+}
+
+// DefaultCatalog is the size of the catalog loaded by the seed command.
+var DefaultCatalog = Catalog{
+	Publishers:     5,
+	Authors:        20,
+	Books:          50,
+	AuthorsPerBook: 3,
+}
+
+// SeedResult counts the rows created by Seed.
+type SeedResult struct {
+	Publishers  int
+	Authors     int
+	Books       int
+	AuthorBooks int
+}
+
+var (
+	givenNames = []string{"Ada", "Alan", "Barbara", "Brian", "Donald", "Edsger", "Frances", "Grace", "Ken", "Margaret", "Niklaus", "Rob", "Radia", "Tony"}
+	surnames   = []string{"Allen", "Dijkstra", "Hamilton", "Hoare", "Hopper", "Kernighan", "Knuth", "Liskov", "Lovelace", "Perlman", "Pike", "Thompson", "Turing", "Wirth"}
+	topics     = []string{"compilers", "concurrency", "databases", "distributed systems", "networking", "operating systems", "programming languages", "type theory"}
+	presses    = []string{"Acorn", "Beacon", "Cobalt", "Driftwood", "Ember", "Fathom", "Granite", "Harbor", "Juniper", "Lantern"}
+	imprints   = []string{"Books", "House", "Press", "Publishing"}
+	adjectives = []string{"Practical", "Concurrent", "Essential", "Modern", "Pragmatic", "Structured", "Effective", "Advanced"}
+	subjects   = []string{"Algorithms", "Compilers", "Databases", "Go", "Networking", "Operating Systems", "SQL", "Testing", "Type Systems"}
+)
+
+// Seed creates a sample catalog of size c with q: publishers, authors with
+// names and mostly a bio, books of random publishers and links from every
+// book to one or more authors. The rows, including their uuids, depend only
+// on seed and c.
+func Seed(ctx context.Context, q sqlc.Querier, seed int64, c Catalog) (SeedResult, error) {
+	var result SeedResult
+	f := NewSeeded(q, seed)
+	r := rand.New(rand.NewSource(seed))
+
+	publishers := make([]sqlc.CreatePublisherParams, c.Publishers)
+	for i := range publishers {
+		// Publisher names are unique, so that imports can refer to them.
+		name := fmt.Sprintf("%s %s", presses[i%len(presses)], imprints[r.Intn(len(imprints))])
+		if i >= len(presses) {
+			name = fmt.Sprintf("%s %d", name, i/len(presses)+1)
+		}
+		p, err := f.Publisher(ctx, func(p *sqlc.CreatePublisherParams) {
+			p.Name = name
+		})
+		if err != nil {
+			return result, err
+		}
+		publishers[i] = p
+		result.Publishers++
+	}
+
+	authors := make([]sqlc.CreateAuthorParams, c.Authors)
+	for i := range authors {
+		name := fmt.Sprintf("%s %s", givenNames[r.Intn(len(givenNames))], surnames[r.Intn(len(surnames))])
+		bio := sql.NullString{}
+		if r.Intn(4) > 0 {
+			bio = sql.NullString{String: fmt.Sprintf("%s writes about %s.", name, topics[r.Intn(len(topics))]), Valid: true}
+		}
+		a, err := f.Author(ctx, func(p *sqlc.CreateAuthorParams) {
+			p.Name = name
+			p.Bio = bio
+		})
+		if err != nil {
+			return result, err
+		}
+		authors[i] = a
+		result.Authors++
+	}
+
+	if c.Books > 0 && (len(publishers) == 0 || len(authors) == 0) {
+		return result, errors.New("books need at least one publisher and one author")
+	}
+	for i := 0; i < c.Books; i++ {
+		title := fmt.Sprintf("%s %s", adjectives[r.Intn(len(adjectives))], subjects[r.Intn(len(subjects))])
+		publisher := publishers[r.Intn(len(publishers))]
+		b, err := f.Book(ctx, func(p *sqlc.CreateBookParams) {
+			p.Title = title
+			p.PublisherUuid = publisher.Uuid
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Books++
+
+		n := 1 + r.Intn(max(min(c.AuthorsPerBook, len(authors)), 1))
+		for _, j := range r.Perm(len(authors))[:n] {
+			if _, err := f.AuthorBook(ctx, func(p *sqlc.CreateAuthorBookParams) {
+				p.AuthorUuid = authors[j].Uuid
+				p.BookUuid = b.Uuid
+			}); err != nil {
+				return result, err
+			}
+			result.AuthorBooks++
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package httpapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type authorBook struct {
+	AuthorUuid binuuid.UUID `json:"author_uuid"`
+	BookUuid   binuuid.UUID `json:"book_uuid"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+}
+
+func newAuthorBook(ab sqlc.AuthorBook) authorBook {
+	return authorBook{
+		AuthorUuid: ab.AuthorUuid,
+		BookUuid:   ab.BookUuid,
+		CreatedAt:  ab.CreatedAt,
+		UpdatedAt:  ab.UpdatedAt,
+	}
+}
+
+type authorBookRequest struct {
+	AuthorUuid binuuid.UUID `json:"author_uuid"`
+	BookUuid   binuuid.UUID `json:"book_uuid"`
+}
+
+type authorBookRow struct {
+	AuthorUuid binuuid.UUID `json:"author_uuid"`
+	AuthorName string       `json:"author_name"`
+	AuthorBio  *string      `json:"author_bio"`
+	BookUuid   binuuid.UUID `json:"book_uuid"`
+	BookTitle  string       `json:"book_title"`
+}
+
+func (s *Server) handleListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := listFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := s.catalog.ListAuthorBooks(r.Context(), req, filter)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, func(row sqlc.ListAuthorBooksPageRow) authorBookRow {
+		return authorBookRow{
+			AuthorUuid: row.AuthorUuid,
+			AuthorName: row.AuthorName,
+			AuthorBio:  stringPtr(row.AuthorBio),
+			BookUuid:   row.BookUuid,
+			BookTitle:  row.BookTitle,
+		}
+	}))
+}
+
+func (s *Server) handleGetAuthorBook(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "author_uuid")
+	if !ok {
+		return
+	}
+	bookUuid, ok := pathUUID(w, r, "book_uuid")
+	if !ok {
+		return
+	}
+
+	ab, err := s.queries.GetAuthorBook(r.Context(), sqlc.GetAuthorBookParams{
+		AuthorUuid: authorUuid,
+		BookUuid:   bookUuid,
+	})
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newAuthorBook(ab))
+}
+
+func (s *Server) handleCreateAuthorBook(w http.ResponseWriter, r *http.Request) {
+	var req authorBookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	ctx := r.Context()
+	err := s.queries.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{
+		AuthorUuid: req.AuthorUuid,
+		BookUuid:   req.BookUuid,
+	})
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	ab, err := s.queries.GetAuthorBook(ctx, sqlc.GetAuthorBookParams{
+		AuthorUuid: req.AuthorUuid,
+		BookUuid:   req.BookUuid,
+	})
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	w.Header().Set("Location", "/authors/"+req.AuthorUuid.String()+"/books/"+req.BookUuid.String())
+	writeJSON(w, http.StatusCreated, newAuthorBook(ab))
+}
+
+func (s *Server) handleDeleteAuthorBook(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "author_uuid")
+	if !ok {
+		return
+	}
+	bookUuid, ok := pathUUID(w, r, "book_uuid")
+	if !ok {
+		return
+	}
+
+	err := dberr.CheckAffected(s.queries.DeleteAuthorBook(r.Context(), sqlc.DeleteAuthorBookParams{
+		AuthorUuid: authorUuid,
+		BookUuid:   bookUuid,
+	}))
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
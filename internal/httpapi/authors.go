@@ -0,0 +1,231 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type author struct {
+	Uuid      binuuid.UUID `json:"uuid"`
+	Name      string       `json:"name"`
+	Bio       *string      `json:"bio"`
+	Version   uint32       `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	DeletedAt *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func newAuthor(a sqlc.Author) author {
+	return author{
+		Uuid:      a.Uuid,
+		Name:      a.Name,
+		Bio:       stringPtr(a.Bio),
+		Version:   a.Version,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+		DeletedAt: timePtr(a.DeletedAt),
+	}
+}
+
+type authorRequest struct {
+	Uuid *binuuid.UUID `json:"uuid,omitempty"`
+	Name string        `json:"name"`
+	Bio  *string       `json:"bio"`
+	// Version is the version the update is based on, unless If-Match is set.
+	Version *uint32 `json:"version,omitempty"`
+}
+
+type deleteAuthorResponse struct {
+	AuthorBooks int64 `json:"author_books"`
+}
+
+func (s *Server) handleListAuthors(w http.ResponseWriter, r *http.Request) {
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := listFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := s.catalog.ListAuthors(r.Context(), req, filter)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newAuthor))
+}
+
+func (s *Server) handleGetAuthor(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	includeDeleted, ok := boolQuery(w, r, "include_deleted")
+	if !ok {
+		return
+	}
+
+	get := s.queries.GetAuthor
+	if includeDeleted {
+		get = s.queries.GetAuthorIncludingDeleted
+	}
+	a, err := get(r.Context(), authorUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, a.Version)
+	writeJSON(w, http.StatusOK, newAuthor(a))
+}
+
+func (s *Server) handleCreateAuthor(w http.ResponseWriter, r *http.Request) {
+	var req authorRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+
+	authorUuid, ok := createUUID(w, req.Uuid)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	err := s.queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{
+		Uuid: authorUuid,
+		Name: req.Name,
+		Bio:  nullString(req.Bio),
+	})
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	a, err := s.queries.GetAuthor(ctx, authorUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, a.Version)
+	w.Header().Set("Location", "/authors/"+authorUuid.String())
+	writeJSON(w, http.StatusCreated, newAuthor(a))
+}
+
+func (s *Server) handleUpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	var req authorRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+
+	version, ifMatch, ok := expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
+
+	a, err := s.catalog.UpdateAuthor(r.Context(), sqlc.UpdateAuthorParams{
+		Name:    req.Name,
+		Bio:     nullString(req.Bio),
+		Uuid:    authorUuid,
+		Version: version,
+	})
+	if err != nil {
+		writeUpdateError(w, err, ifMatch)
+		return
+	}
+	setETag(w, a.Version)
+	writeJSON(w, http.StatusOK, newAuthor(a))
+}
+
+func (s *Server) handleDeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	cascade, ok := boolQuery(w, r, "cascade")
+	if !ok {
+		return
+	}
+
+	if cascade {
+		result, err := s.catalog.DeleteAuthorCascade(r.Context(), authorUuid)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, deleteAuthorResponse{AuthorBooks: result.AuthorBooks})
+		return
+	}
+
+	err := s.catalog.DeleteAuthor(r.Context(), authorUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRestoreAuthor(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	err := dberr.CheckAffected(s.queries.RestoreAuthor(ctx, authorUuid))
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	a, err := s.queries.GetAuthor(ctx, authorUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, a.Version)
+	writeJSON(w, http.StatusOK, newAuthor(a))
+}
+
+func (s *Server) handleListBooksOfAuthor(w http.ResponseWriter, r *http.Request) {
+	authorUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := s.queries.GetAuthor(ctx, authorUuid); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	page, err := s.catalog.ListBooksByAuthor(ctx, authorUuid, req)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newBook))
+}
@@ -0,0 +1,243 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type book struct {
+	Uuid          binuuid.UUID `json:"uuid"`
+	Title         string       `json:"title"`
+	PublisherUuid binuuid.UUID `json:"publisher_uuid"`
+	Version       uint32       `json:"version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+	DeletedAt     *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func newBook(b sqlc.Book) book {
+	return book{
+		Uuid:          b.Uuid,
+		Title:         b.Title,
+		PublisherUuid: b.PublisherUuid,
+		Version:       b.Version,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+		DeletedAt:     timePtr(b.DeletedAt),
+	}
+}
+
+type bookRequest struct {
+	Uuid          *binuuid.UUID `json:"uuid,omitempty"`
+	Title         string        `json:"title"`
+	PublisherUuid binuuid.UUID  `json:"publisher_uuid"`
+}
+
+// bookUpdateRequest has only the fields an update changes, so that a
+// publisher_uuid is rejected rather than ignored.
+type bookUpdateRequest struct {
+	Title string `json:"title"`
+	// Version is the version the update is based on, unless If-Match is set.
+	Version *uint32 `json:"version,omitempty"`
+}
+
+type bookPublisher struct {
+	BookUuid      binuuid.UUID `json:"book_uuid"`
+	BookTitle     string       `json:"book_title"`
+	PublisherUuid binuuid.UUID `json:"publisher_uuid"`
+	PublisherName string       `json:"publisher_name"`
+}
+
+func (s *Server) handleListBooks(w http.ResponseWriter, r *http.Request) {
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := listFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := s.catalog.ListBooks(r.Context(), req, filter)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newBook))
+}
+
+func (s *Server) handleGetBook(w http.ResponseWriter, r *http.Request) {
+	bookUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	includeDeleted, ok := boolQuery(w, r, "include_deleted")
+	if !ok {
+		return
+	}
+
+	get := s.queries.GetBook
+	if includeDeleted {
+		get = s.queries.GetBookIncludingDeleted
+	}
+	b, err := get(r.Context(), bookUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, b.Version)
+	writeJSON(w, http.StatusOK, newBook(b))
+}
+
+func (s *Server) handleCreateBook(w http.ResponseWriter, r *http.Request) {
+	var req bookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, errors.New("title is required"))
+		return
+	}
+
+	bookUuid, ok := createUUID(w, req.Uuid)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	err := s.queries.CreateBook(ctx, sqlc.CreateBookParams{
+		Uuid:          bookUuid,
+		Title:         req.Title,
+		PublisherUuid: req.PublisherUuid,
+	})
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	b, err := s.queries.GetBook(ctx, bookUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, b.Version)
+	w.Header().Set("Location", "/books/"+bookUuid.String())
+	writeJSON(w, http.StatusCreated, newBook(b))
+}
+
+func (s *Server) handleUpdateBook(w http.ResponseWriter, r *http.Request) {
+	bookUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	var req bookUpdateRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Title == "" {
+		writeError(w, http.StatusBadRequest, errors.New("title is required"))
+		return
+	}
+
+	version, ifMatch, ok := expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
+
+	b, err := s.catalog.UpdateBook(r.Context(), sqlc.UpdateBookParams{
+		Title:   req.Title,
+		Uuid:    bookUuid,
+		Version: version,
+	})
+	if err != nil {
+		writeUpdateError(w, err, ifMatch)
+		return
+	}
+	setETag(w, b.Version)
+	writeJSON(w, http.StatusOK, newBook(b))
+}
+
+func (s *Server) handleDeleteBook(w http.ResponseWriter, r *http.Request) {
+	bookUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	err := s.catalog.DeleteBook(r.Context(), bookUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRestoreBook(w http.ResponseWriter, r *http.Request) {
+	bookUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	err := dberr.CheckAffected(s.queries.RestoreBook(ctx, bookUuid))
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	b, err := s.queries.GetBook(ctx, bookUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, b.Version)
+	writeJSON(w, http.StatusOK, newBook(b))
+}
+
+func (s *Server) handleGetBookPublisher(w http.ResponseWriter, r *http.Request) {
+	bookUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	row, err := s.queries.GetBookPublisher(r.Context(), bookUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, bookPublisher{
+		BookUuid:      row.BookUuid,
+		BookTitle:     row.BookTitle,
+		PublisherUuid: row.PublisherUuid,
+		PublisherName: row.PublisherName,
+	})
+}
+
+func (s *Server) handleListAuthorsOfBook(w http.ResponseWriter, r *http.Request) {
+	bookUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := s.queries.GetBook(ctx, bookUuid); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	page, err := s.catalog.ListAuthorsByBook(ctx, bookUuid, req)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newAuthor))
+}
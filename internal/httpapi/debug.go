@@ -0,0 +1,20 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/database"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+)
+
+// NewDebug returns the handler of the debug endpoints, which expose
+// internals such as the connection pool statistics of db. It carries no
+// authentication, so it is meant to be served on a separate listener that
+// only operators can reach, never next to the API.
+func NewDebug(db *dbtx.DB) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/db-stats", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, database.StatsOf(db.Unwrap()))
+	})
+	return mux
+}
@@ -0,0 +1,245 @@
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type publisher struct {
+	Uuid      binuuid.UUID `json:"uuid"`
+	Name      string       `json:"name"`
+	Version   uint32       `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+	DeletedAt *time.Time   `json:"deleted_at,omitempty"`
+}
+
+func newPublisher(p sqlc.Publisher) publisher {
+	return publisher{
+		Uuid:      p.Uuid,
+		Name:      p.Name,
+		Version:   p.Version,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+		DeletedAt: timePtr(p.DeletedAt),
+	}
+}
+
+type publisherRequest struct {
+	Uuid *binuuid.UUID `json:"uuid,omitempty"`
+	Name string        `json:"name"`
+	// Version is the version the update is based on, unless If-Match is set.
+	Version *uint32 `json:"version,omitempty"`
+}
+
+type deletePublisherResponse struct {
+	Books int64 `json:"books"`
+}
+
+type publisherBook struct {
+	PublisherUuid binuuid.UUID `json:"publisher_uuid"`
+	PublisherName string       `json:"publisher_name"`
+	BookUuid      binuuid.UUID `json:"book_uuid"`
+	BookTitle     string       `json:"book_title"`
+}
+
+func (s *Server) handleListPublishers(w http.ResponseWriter, r *http.Request) {
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	filter, ok := listFilter(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := s.catalog.ListPublishers(r.Context(), req, filter)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newPublisher))
+}
+
+func (s *Server) handleGetPublisher(w http.ResponseWriter, r *http.Request) {
+	publisherUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	includeDeleted, ok := boolQuery(w, r, "include_deleted")
+	if !ok {
+		return
+	}
+
+	get := s.queries.GetPublisher
+	if includeDeleted {
+		get = s.queries.GetPublisherIncludingDeleted
+	}
+	p, err := get(r.Context(), publisherUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, p.Version)
+	writeJSON(w, http.StatusOK, newPublisher(p))
+}
+
+func (s *Server) handleCreatePublisher(w http.ResponseWriter, r *http.Request) {
+	var req publisherRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+
+	publisherUuid, ok := createUUID(w, req.Uuid)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	err := s.queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{
+		Uuid: publisherUuid,
+		Name: req.Name,
+	})
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	p, err := s.queries.GetPublisher(ctx, publisherUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, p.Version)
+	w.Header().Set("Location", "/publishers/"+publisherUuid.String())
+	writeJSON(w, http.StatusCreated, newPublisher(p))
+}
+
+func (s *Server) handleUpdatePublisher(w http.ResponseWriter, r *http.Request) {
+	publisherUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+	var req publisherRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeError(w, http.StatusBadRequest, errors.New("name is required"))
+		return
+	}
+
+	version, ifMatch, ok := expectedVersion(w, r, req.Version)
+	if !ok {
+		return
+	}
+
+	p, err := s.catalog.UpdatePublisher(r.Context(), sqlc.UpdatePublisherParams{
+		Name:    req.Name,
+		Uuid:    publisherUuid,
+		Version: version,
+	})
+	if err != nil {
+		writeUpdateError(w, err, ifMatch)
+		return
+	}
+	setETag(w, p.Version)
+	writeJSON(w, http.StatusOK, newPublisher(p))
+}
+
+func (s *Server) handleDeletePublisher(w http.ResponseWriter, r *http.Request) {
+	publisherUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	if v := r.URL.Query().Get("reassign_to"); v != "" {
+		to, err := binuuid.Parse(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid reassign_to: %w", err))
+			return
+		}
+		if to == publisherUuid {
+			writeError(w, http.StatusBadRequest, errors.New("cannot reassign books to the deleted publisher"))
+			return
+		}
+
+		result, err := s.catalog.DeletePublisherReassign(r.Context(), publisherUuid, to)
+		if err != nil {
+			writeQueryError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, deletePublisherResponse{Books: result.Books})
+		return
+	}
+
+	err := s.catalog.DeletePublisher(r.Context(), publisherUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRestorePublisher(w http.ResponseWriter, r *http.Request) {
+	publisherUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	err := dberr.CheckAffected(s.queries.RestorePublisher(ctx, publisherUuid))
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	p, err := s.queries.GetPublisher(ctx, publisherUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	setETag(w, p.Version)
+	writeJSON(w, http.StatusOK, newPublisher(p))
+}
+
+func (s *Server) handleGetPublisherBooks(w http.ResponseWriter, r *http.Request) {
+	publisherUuid, ok := pathUUID(w, r, "uuid")
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	if _, err := s.queries.GetPublisher(ctx, publisherUuid); err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	rows, err := s.queries.GetPublisherBooks(ctx, publisherUuid)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+
+	res := make([]publisherBook, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, publisherBook{
+			PublisherUuid: row.PublisherUuid,
+			PublisherName: row.PublisherName,
+			BookUuid:      row.BookUuid,
+			BookTitle:     row.BookTitle,
+		})
+	}
+	writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,90 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type bookSearchResult struct {
+	BookUuid      binuuid.UUID `json:"book_uuid"`
+	BookTitle     string       `json:"book_title"`
+	PublisherUuid binuuid.UUID `json:"publisher_uuid"`
+	PublisherName string       `json:"publisher_name"`
+	Score         float64      `json:"score"`
+}
+
+func newBookSearchResult(row sqlc.SearchBooksRow) bookSearchResult {
+	return bookSearchResult{
+		BookUuid:      row.BookUuid,
+		BookTitle:     row.BookTitle,
+		PublisherUuid: row.PublisherUuid,
+		PublisherName: row.PublisherName,
+		Score:         row.Score,
+	}
+}
+
+type authorSearchResult struct {
+	Uuid  binuuid.UUID `json:"uuid"`
+	Name  string       `json:"name"`
+	Bio   *string      `json:"bio"`
+	Score float64      `json:"score"`
+}
+
+func newAuthorSearchResult(row sqlc.SearchAuthorsRow) authorSearchResult {
+	return authorSearchResult{
+		Uuid:  row.Uuid,
+		Name:  row.Name,
+		Bio:   stringPtr(row.Bio),
+		Score: row.Score,
+	}
+}
+
+func (s *Server) handleSearchBooks(w http.ResponseWriter, r *http.Request) {
+	query, ok := searchQuery(w, r)
+	if !ok {
+		return
+	}
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := s.catalog.SearchBooks(r.Context(), query, req)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newBookSearchResult))
+}
+
+func (s *Server) handleSearchAuthors(w http.ResponseWriter, r *http.Request) {
+	query, ok := searchQuery(w, r)
+	if !ok {
+		return
+	}
+	req, ok := pageRequest(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := s.catalog.SearchAuthors(r.Context(), query, req)
+	if err != nil {
+		writeQueryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, newPageResponse(page, newAuthorSearchResult))
+}
+
+// searchQuery reads the q query parameter and writes a 400 response when it
+// is missing.
+func searchQuery(w http.ResponseWriter, r *http.Request) (string, bool) {
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("q is required"))
+		return "", false
+	}
+	return query, true
+}
@@ -0,0 +1,327 @@
+// Package httpapi exposes the catalog as a JSON HTTP API.
+package httpapi
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/requestid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+type Server struct {
+	db      sqlc.DBTX
+	queries *sqlc.Queries
+	catalog *catalog.Service
+	mux     *http.ServeMux
+}
+
+// New returns a server that runs its queries against db, which is usually a
+// *sql.DB or a *dbtx.DB but may be a *sql.Tx in tests.
+func New(db sqlc.DBTX) *Server {
+	s := &Server{
+		db:      db,
+		queries: sqlc.New(db),
+		catalog: catalog.New(db),
+		mux:     http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+// ServeHTTP serves r with the request ID of its requestid.Header, or a new
+// one if it has none, and echoes the ID in the response.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get(requestid.Header)
+	if !requestid.Valid(id) {
+		id = requestid.New()
+	}
+	w.Header().Set(requestid.Header, id)
+	s.mux.ServeHTTP(w, r.WithContext(requestid.With(r.Context(), id)))
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /healthz", s.handleHealthz)
+
+	s.mux.HandleFunc("GET /authors", s.handleListAuthors)
+	s.mux.HandleFunc("POST /authors", s.handleCreateAuthor)
+	s.mux.HandleFunc("GET /authors/{uuid}", s.handleGetAuthor)
+	s.mux.HandleFunc("PUT /authors/{uuid}", s.handleUpdateAuthor)
+	s.mux.HandleFunc("DELETE /authors/{uuid}", s.handleDeleteAuthor)
+	s.mux.HandleFunc("POST /authors/{uuid}/restore", s.handleRestoreAuthor)
+	s.mux.HandleFunc("GET /authors/{uuid}/books", s.handleListBooksOfAuthor)
+	s.mux.HandleFunc("GET /authors/{author_uuid}/books/{book_uuid}", s.handleGetAuthorBook)
+	s.mux.HandleFunc("DELETE /authors/{author_uuid}/books/{book_uuid}", s.handleDeleteAuthorBook)
+
+	s.mux.HandleFunc("GET /publishers", s.handleListPublishers)
+	s.mux.HandleFunc("POST /publishers", s.handleCreatePublisher)
+	s.mux.HandleFunc("GET /publishers/{uuid}", s.handleGetPublisher)
+	s.mux.HandleFunc("PUT /publishers/{uuid}", s.handleUpdatePublisher)
+	s.mux.HandleFunc("DELETE /publishers/{uuid}", s.handleDeletePublisher)
+	s.mux.HandleFunc("POST /publishers/{uuid}/restore", s.handleRestorePublisher)
+	s.mux.HandleFunc("GET /publishers/{uuid}/books", s.handleGetPublisherBooks)
+
+	s.mux.HandleFunc("GET /books", s.handleListBooks)
+	s.mux.HandleFunc("POST /books", s.handleCreateBook)
+	s.mux.HandleFunc("GET /books/{uuid}", s.handleGetBook)
+	s.mux.HandleFunc("PUT /books/{uuid}", s.handleUpdateBook)
+	s.mux.HandleFunc("DELETE /books/{uuid}", s.handleDeleteBook)
+	s.mux.HandleFunc("POST /books/{uuid}/restore", s.handleRestoreBook)
+	s.mux.HandleFunc("GET /books/{uuid}/publisher", s.handleGetBookPublisher)
+	s.mux.HandleFunc("GET /books/{uuid}/authors", s.handleListAuthorsOfBook)
+
+	s.mux.HandleFunc("GET /author-books", s.handleListAuthorBooks)
+	s.mux.HandleFunc("POST /author-books", s.handleCreateAuthorBook)
+
+	s.mux.HandleFunc("GET /search/books", s.handleSearchBooks)
+	s.mux.HandleFunc("GET /search/authors", s.handleSearchAuthors)
+}
+
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if pinger, ok := s.db.(interface {
+		PingContext(ctx context.Context) error
+	}); ok {
+		if err := pinger.PingContext(r.Context()); err != nil {
+			writeError(w, http.StatusServiceUnavailable, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// pathUUID parses the named path parameter as a UUID and writes a 400
+// response when it is malformed.
+func pathUUID(w http.ResponseWriter, r *http.Request, name string) (binuuid.UUID, bool) {
+	id, err := binuuid.Parse(r.PathValue(name))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s: %w", name, err))
+		return binuuid.UUID{}, false
+	}
+	return id, true
+}
+
+// createUUID returns the uuid a create request names, or a new one when it
+// names none, and writes a 400 response when it is binuuid.Nil.
+func createUUID(w http.ResponseWriter, id *binuuid.UUID) (binuuid.UUID, bool) {
+	if id == nil {
+		return binuuid.New(), true
+	}
+	if *id == binuuid.Nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid uuid: %w", binuuid.ErrNil))
+		return binuuid.UUID{}, false
+	}
+	return *id, true
+}
+
+// pageRequest reads the limit and cursor query parameters and writes a 400
+// response when they are malformed.
+func pageRequest(w http.ResponseWriter, r *http.Request) (pagination.Request, bool) {
+	var req pagination.Request
+	query := r.URL.Query()
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid limit %q", v))
+			return pagination.Request{}, false
+		}
+		req.Limit = limit
+	}
+	req.Cursor = query.Get("cursor")
+
+	return req, true
+}
+
+// listFilter reads the created_from, created_to, updated_from and
+// updated_to query parameters as RFC 3339 timestamps, and include_deleted,
+// and writes a 400 response when they are malformed.
+func listFilter(w http.ResponseWriter, r *http.Request) (catalog.ListFilter, bool) {
+	var filter catalog.ListFilter
+	query := r.URL.Query()
+
+	params := map[string]*time.Time{
+		"created_from": &filter.Created.From,
+		"created_to":   &filter.Created.To,
+		"updated_from": &filter.Updated.From,
+		"updated_to":   &filter.Updated.To,
+	}
+	for name, dst := range params {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q", name, v))
+			return catalog.ListFilter{}, false
+		}
+		*dst = t
+	}
+
+	includeDeleted, ok := boolQuery(w, r, "include_deleted")
+	if !ok {
+		return catalog.ListFilter{}, false
+	}
+	filter.IncludeDeleted = includeDeleted
+
+	return filter, true
+}
+
+// boolQuery reads the named query parameter as a boolean, false when it is
+// absent, and writes a 400 response when it is malformed.
+func boolQuery(w http.ResponseWriter, r *http.Request, name string) (bool, bool) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return false, true
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid %s %q", name, v))
+		return false, false
+	}
+	return b, true
+}
+
+// expectedVersion returns the row version an update is based on and
+// whether it was taken from the If-Match header rather than the version
+// field of the request body. It writes a 400 response when If-Match is
+// malformed and a 428 response when neither is set.
+func expectedVersion(w http.ResponseWriter, r *http.Request, body *uint32) (uint32, bool, bool) {
+	if v := r.Header.Get("If-Match"); v != "" {
+		version, err := strconv.ParseUint(strings.Trim(v, `"`), 10, 32)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid If-Match %q", v))
+			return 0, false, false
+		}
+		return uint32(version), true, true
+	}
+	if body != nil {
+		return *body, false, true
+	}
+	writeError(w, http.StatusPreconditionRequired, errors.New("version or If-Match is required"))
+	return 0, false, false
+}
+
+// setETag exposes the version of a row as a strong entity tag, to be sent
+// back in If-Match.
+func setETag(w http.ResponseWriter, version uint32) {
+	w.Header().Set("ETag", `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+}
+
+type pageResponse[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func newPageResponse[S, T any](page pagination.Page[S], convert func(S) T) pageResponse[T] {
+	items := make([]T, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+	return pageResponse[T]{
+		Items:      items,
+		NextCursor: page.NextCursor,
+	}
+}
+
+// decodeJSON decodes the request body into dst and writes a 400 response
+// when it is malformed.
+func decodeJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+// writeUpdateError is writeQueryError for conditional updates. A version
+// conflict is reported as 412 when the version came from If-Match.
+func writeUpdateError(w http.ResponseWriter, err error, ifMatch bool) {
+	if ifMatch && errors.Is(err, dberr.ErrConflict) {
+		writeError(w, http.StatusPreconditionFailed, dberr.ErrConflict)
+		return
+	}
+	writeQueryError(w, err)
+}
+
+// writeQueryError maps an error returned by sqlc.Queries or the catalog
+// service to a response.
+func writeQueryError(w http.ResponseWriter, err error) {
+	err = dberr.Translate(err)
+	switch {
+	case errors.Is(err, dberr.ErrNotFound):
+		writeError(w, http.StatusNotFound, dberr.ErrNotFound)
+		return
+	case errors.Is(err, dberr.ErrAlreadyExists):
+		writeError(w, http.StatusConflict, dberr.ErrAlreadyExists)
+		return
+	case errors.Is(err, dberr.ErrInUse):
+		writeError(w, http.StatusConflict, dberr.ErrInUse)
+		return
+	case errors.Is(err, dberr.ErrConflict):
+		writeError(w, http.StatusConflict, dberr.ErrConflict)
+		return
+	case errors.Is(err, dberr.ErrReferenceMissing):
+		writeError(w, http.StatusUnprocessableEntity, dberr.ErrReferenceMissing)
+		return
+	case errors.Is(err, pagination.ErrInvalidCursor):
+		writeError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, catalog.ErrInvalidSearch):
+		writeError(w, http.StatusBadRequest, catalog.ErrInvalidSearch)
+		return
+	}
+	log.Printf("httpapi: %s", err)
+	writeError(w, http.StatusInternalServerError, errors.New("internal server error"))
+}
+
+func nullString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *s, Valid: true}
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("httpapi: encode response: %s", err)
+	}
+}
@@ -0,0 +1,387 @@
+// Package importer loads authors, publishers, books and author-book links
+// from CSV or JSON files into the catalog.
+//
+// Every file holds one kind of row. CSV files start with a header row and
+// JSON files hold an array of objects with string values; the columns are
+//
+//	publishers:   uuid, name
+//	authors:      uuid, name, bio
+//	books:        uuid, title, publisher_uuid | publisher_name
+//	author_books: author_uuid, book_uuid
+//
+// A missing uuid is generated. A book names its publisher either by uuid or
+// by name; names are looked up among the live publishers, including those
+// imported by the same run, and must be unique.
+//
+// All files are written in one transaction, publishers first and links
+// last. Rows that are invalid or rejected by the database are reported
+// with their line number and roll back the whole import unless
+// Options.Partial is set; rows whose uuid already exists are skipped.
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+// errDryRun rolls back the transaction of a dry run.
+var errDryRun = errors.New("dry run")
+
+// errRejected rolls back the transaction of an import with rejected rows.
+var errRejected = errors.New("rows rejected")
+
+// Input is one file to import.
+type Input struct {
+	// Name identifies the input in the report, e.g. its path.
+	Name   string
+	Format Format
+	Reader io.Reader
+}
+
+// Inputs are the files of one import. Nil inputs are skipped.
+type Inputs struct {
+	Publishers  *Input
+	Authors     *Input
+	Books       *Input
+	AuthorBooks *Input
+}
+
+type Options struct {
+	// DryRun validates and writes the rows as usual but rolls back the
+	// transaction, so the report shows what an import would do.
+	DryRun bool
+	// Partial writes the valid rows even if others are rejected. Without
+	// it, a rejected row rolls back the transaction and the report shows
+	// what would have been written.
+	Partial bool
+}
+
+// Report describes the outcome of an import, one entry per input in the
+// order they were written.
+type Report struct {
+	Files []FileReport
+}
+
+type FileReport struct {
+	Name     string
+	Inserted int
+	// Skipped lists rows whose uuid already exists.
+	Skipped []Issue
+	// Rejected lists rows that are invalid or reference missing rows.
+	Rejected []Issue
+}
+
+// Issue is a row that was not inserted.
+type Issue struct {
+	Line   int
+	Reason string
+}
+
+// Rejected returns the number of rejected rows over all files.
+func (r Report) Rejected() int {
+	n := 0
+	for _, file := range r.Files {
+		n += len(file.Rejected)
+	}
+	return n
+}
+
+// Write writes a human-readable summary of r to w.
+func (r Report) Write(w io.Writer) error {
+	var b strings.Builder
+	for _, file := range r.Files {
+		fmt.Fprintf(&b, "%s: %d inserted, %d skipped, %d rejected\n",
+			file.Name, file.Inserted, len(file.Skipped), len(file.Rejected))
+		for _, issue := range file.Skipped {
+			fmt.Fprintf(&b, "  line %d: skipped: %s\n", issue.Line, issue.Reason)
+		}
+		for _, issue := range file.Rejected {
+			fmt.Fprintf(&b, "  line %d: rejected: %s\n", issue.Line, issue.Reason)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Import reads in and writes its rows with svc in one transaction. Errors
+// other than those of single rows, e.g. a malformed file or a lost
+// connection, roll back the whole import.
+func Import(ctx context.Context, svc *catalog.Service, in Inputs, opts Options) (Report, error) {
+	var report Report
+
+	err := svc.Atomic(ctx, func(svc *catalog.Service) error {
+		im := &importer{
+			svc:        svc,
+			publishers: make(map[string]publisherLookup),
+		}
+		steps := []struct {
+			input *Input
+			run   func(context.Context, *Input) (FileReport, error)
+		}{
+			{in.Publishers, im.importPublishers},
+			{in.Authors, im.importAuthors},
+			{in.Books, im.importBooks},
+			{in.AuthorBooks, im.importAuthorBooks},
+		}
+		for _, step := range steps {
+			if step.input == nil {
+				continue
+			}
+			file, err := step.run(ctx, step.input)
+			if err != nil {
+				return fmt.Errorf("%s: %w", step.input.Name, err)
+			}
+			report.Files = append(report.Files, file)
+		}
+
+		if opts.DryRun {
+			return errDryRun
+		}
+		if !opts.Partial && report.Rejected() > 0 {
+			return errRejected
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) && !errors.Is(err, errRejected) {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+type importer struct {
+	svc *catalog.Service
+	// publishers caches publisher name lookups.
+	publishers map[string]publisherLookup
+}
+
+type publisherLookup struct {
+	uuid   binuuid.UUID
+	reason string
+}
+
+func (im *importer) importPublishers(ctx context.Context, in *Input) (FileReport, error) {
+	return importFile(ctx, in, []string{"uuid", "name"},
+		func(rec record) (sqlc.CreatePublisherParams, error) {
+			var p sqlc.CreatePublisherParams
+			var err error
+			if p.Uuid, err = optionalUUID(rec, "uuid"); err != nil {
+				return p, err
+			}
+			p.Name, err = required(rec, "name")
+			return p, err
+		},
+		im.svc.BulkCreatePublishers,
+	)
+}
+
+func (im *importer) importAuthors(ctx context.Context, in *Input) (FileReport, error) {
+	return importFile(ctx, in, []string{"uuid", "name", "bio"},
+		func(rec record) (sqlc.CreateAuthorParams, error) {
+			var p sqlc.CreateAuthorParams
+			var err error
+			if p.Uuid, err = optionalUUID(rec, "uuid"); err != nil {
+				return p, err
+			}
+			if p.Name, err = required(rec, "name"); err != nil {
+				return p, err
+			}
+			bio, ok := rec.fields["bio"]
+			p.Bio = sql.NullString{String: bio, Valid: ok}
+			return p, nil
+		},
+		im.svc.BulkCreateAuthors,
+	)
+}
+
+func (im *importer) importBooks(ctx context.Context, in *Input) (FileReport, error) {
+	var lookupErr error
+
+	report, err := importFile(ctx, in, []string{"uuid", "title", "publisher_uuid", "publisher_name"},
+		func(rec record) (sqlc.CreateBookParams, error) {
+			var p sqlc.CreateBookParams
+			var err error
+			if p.Uuid, err = optionalUUID(rec, "uuid"); err != nil {
+				return p, err
+			}
+			if p.Title, err = required(rec, "title"); err != nil {
+				return p, err
+			}
+
+			_, hasUUID := rec.fields["publisher_uuid"]
+			name, hasName := rec.fields["publisher_name"]
+			switch {
+			case hasUUID && hasName:
+				return p, errors.New("set either publisher_uuid or publisher_name, not both")
+			case hasUUID:
+				p.PublisherUuid, err = optionalUUID(rec, "publisher_uuid")
+				return p, err
+			case hasName:
+				lookup, err := im.lookupPublisher(ctx, name)
+				if err != nil {
+					// Not a row error; abort the import after the file is read.
+					lookupErr = err
+					return p, err
+				}
+				if lookup.reason != "" {
+					return p, errors.New(lookup.reason)
+				}
+				p.PublisherUuid = lookup.uuid
+				return p, nil
+			}
+			return p, errors.New("publisher_uuid or publisher_name is required")
+		},
+		im.svc.BulkCreateBooks,
+	)
+	if lookupErr != nil {
+		return FileReport{}, lookupErr
+	}
+	return report, err
+}
+
+func (im *importer) importAuthorBooks(ctx context.Context, in *Input) (FileReport, error) {
+	return importFile(ctx, in, []string{"author_uuid", "book_uuid"},
+		func(rec record) (sqlc.CreateAuthorBookParams, error) {
+			var p sqlc.CreateAuthorBookParams
+			var err error
+			if p.AuthorUuid, err = requiredUUID(rec, "author_uuid"); err != nil {
+				return p, err
+			}
+			p.BookUuid, err = requiredUUID(rec, "book_uuid")
+			return p, err
+		},
+		im.svc.BulkCreateAuthorBooks,
+	)
+}
+
+// lookupPublisher resolves a publisher name to the uuid of the only live
+// publisher of that name. Names that match no or several publishers are
+// reported with a reason instead.
+func (im *importer) lookupPublisher(ctx context.Context, name string) (publisherLookup, error) {
+	if lookup, ok := im.publishers[name]; ok {
+		return lookup, nil
+	}
+
+	publishers, err := im.svc.Queries().ListPublishersByName(ctx, name)
+	if err != nil {
+		return publisherLookup{}, fmt.Errorf("look up publisher %q: %w", name, err)
+	}
+
+	var lookup publisherLookup
+	switch len(publishers) {
+	case 0:
+		lookup.reason = fmt.Sprintf("publisher %q does not exist", name)
+	case 1:
+		lookup.uuid = publishers[0].Uuid
+	default:
+		lookup.reason = fmt.Sprintf("publisher name %q is ambiguous", name)
+	}
+	im.publishers[name] = lookup
+	return lookup, nil
+}
+
+// importFile reads the records of in, converts the valid ones with parse
+// and inserts them with create.
+func importFile[T any](
+	ctx context.Context,
+	in *Input,
+	columns []string,
+	parse func(record) (T, error),
+	create func(context.Context, []T) (catalog.BulkResult, error),
+) (FileReport, error) {
+	report := FileReport{Name: in.Name}
+
+	records, err := readRecords(in.Reader, in.Format, columns)
+	if err != nil {
+		return FileReport{}, err
+	}
+
+	rows := make([]T, 0, len(records))
+	lines := make([]int, 0, len(records))
+	for _, rec := range records {
+		row, err := parse(rec)
+		if err != nil {
+			report.Rejected = append(report.Rejected, Issue{Line: rec.line, Reason: err.Error()})
+			continue
+		}
+		rows = append(rows, row)
+		lines = append(lines, rec.line)
+	}
+	if err := ctx.Err(); err != nil {
+		return FileReport{}, err
+	}
+
+	result, err := create(ctx, rows)
+	if err != nil {
+		return FileReport{}, err
+	}
+	report.Inserted = result.Inserted
+	for _, failure := range result.Failures {
+		issue := Issue{Line: lines[failure.Index], Reason: reason(failure.Err)}
+		if errors.Is(failure.Err, dberr.ErrAlreadyExists) {
+			report.Skipped = append(report.Skipped, issue)
+		} else {
+			report.Rejected = append(report.Rejected, issue)
+		}
+	}
+	sortIssues(report.Skipped)
+	sortIssues(report.Rejected)
+	return report, nil
+}
+
+// reason describes a row failure by its domain error alone; the driver
+// message adds nothing for the reader of a report.
+func reason(err error) string {
+	for _, kind := range []error{dberr.ErrAlreadyExists, dberr.ErrReferenceMissing} {
+		if errors.Is(err, kind) {
+			return kind.Error()
+		}
+	}
+	return err.Error()
+}
+
+func sortIssues(issues []Issue) {
+	slices.SortStableFunc(issues, func(a, b Issue) int {
+		return a.Line - b.Line
+	})
+}
+
+func required(rec record, column string) (string, error) {
+	value, ok := rec.fields[column]
+	if !ok {
+		return "", fmt.Errorf("%s is required", column)
+	}
+	return value, nil
+}
+
+func requiredUUID(rec record, column string) (binuuid.UUID, error) {
+	if _, ok := rec.fields[column]; !ok {
+		return binuuid.UUID{}, fmt.Errorf("%s is required", column)
+	}
+	return optionalUUID(rec, column)
+}
+
+// optionalUUID parses a uuid column, generating a new uuid when it is empty.
+func optionalUUID(rec record, column string) (binuuid.UUID, error) {
+	value, ok := rec.fields[column]
+	if !ok {
+		return binuuid.New(), nil
+	}
+	id, err := binuuid.Parse(value)
+	if err != nil {
+		return binuuid.UUID{}, fmt.Errorf("invalid %s %q", column, value)
+	}
+	if id == binuuid.Nil {
+		return binuuid.UUID{}, fmt.Errorf("invalid %s: %w", column, binuuid.ErrNil)
+	}
+	return id, nil
+}
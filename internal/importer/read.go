@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// FormatOf derives the format of a file from its extension.
+func FormatOf(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("%s: unknown format, want .csv or .json", path)
+}
+
+// record is one input row: its line number and its fields by column name.
+// Empty and null fields are absent.
+type record struct {
+	line   int
+	fields map[string]string
+}
+
+// readRecords reads every record of r. Columns other than columns are an
+// error, so that typos in headers are not silently ignored.
+func readRecords(r io.Reader, format Format, columns []string) ([]record, error) {
+	var (
+		records []record
+		err     error
+	)
+	switch format {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatJSON:
+		records, err = readJSON(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+	for _, rec := range records {
+		for name := range rec.fields {
+			if !known[name] {
+				return nil, fmt.Errorf("line %d: unknown column %q", rec.line, name)
+			}
+		}
+	}
+	return records, nil
+}
+
+// readCSV reads a CSV file with a header row.
+func readCSV(r io.Reader) ([]record, error) {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	header = append([]string(nil), header...)
+
+	var records []record
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// The reader rejects rows whose length differs from the header.
+		line, _ := cr.FieldPos(0)
+		rec := record{line: line, fields: make(map[string]string, len(row))}
+		for i, value := range row {
+			if value != "" {
+				rec.fields[header[i]] = value
+			}
+		}
+		records = append(records, rec)
+	}
+}
+
+// readJSON reads a JSON array of objects with string or null values. The
+// line of a record is the line its object starts on.
+func readJSON(r io.Reader) ([]record, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return nil, errors.New("want a JSON array of objects")
+	}
+
+	var (
+		records []record
+		offset  int
+		line    = 1
+	)
+	for dec.More() {
+		// Skip to the start of the object to find its line.
+		start := int(dec.InputOffset())
+		for start < len(data) && strings.ContainsRune(" \t\r\n,", rune(data[start])) {
+			start++
+		}
+		line += bytes.Count(data[offset:start], []byte("\n"))
+		offset = start
+
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rec := record{line: line, fields: make(map[string]string, len(obj))}
+		for name, value := range obj {
+			switch v := value.(type) {
+			case nil:
+			case string:
+				if v != "" {
+					rec.fields[name] = v
+				}
+			default:
+				return nil, fmt.Errorf("line %d: %s must be a string", line, name)
+			}
+		}
+		records = append(records, rec)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
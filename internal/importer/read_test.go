@@ -0,0 +1,138 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadRecords(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			format Format
+			data   string
+		}
+		expected []record
+	}{
+		{
+			scenario: "csv",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatCSV,
+				data:   "uuid,name,bio\n,Rob Pike,\n,\"Ken\nThompson\",Unix\n",
+			},
+			expected: []record{
+				{line: 2, fields: map[string]string{"name": "Rob Pike"}},
+				{line: 3, fields: map[string]string{"name": "Ken\nThompson", "bio": "Unix"}},
+			},
+		},
+		{
+			scenario: "json",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatJSON,
+				data:   "[\n  {\"name\": \"Rob Pike\", \"bio\": null},\n\n  {\n    \"name\": \"Ken Thompson\",\n    \"bio\": \"Unix\"\n  }\n]\n",
+			},
+			expected: []record{
+				{line: 2, fields: map[string]string{"name": "Rob Pike"}},
+				{line: 4, fields: map[string]string{"name": "Ken Thompson", "bio": "Unix"}},
+			},
+		},
+		{
+			scenario: "empty json",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatJSON,
+				data:   "[]",
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			records, err := readRecords(strings.NewReader(tt.input.data), tt.input.format, []string{"uuid", "name", "bio"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(records, tt.expected) {
+				t.Errorf("got=%v, want=%v", records, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadRecordsInvalid(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			format Format
+			data   string
+		}
+		expected string
+	}{
+		{
+			scenario: "unknown csv column",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatCSV,
+				data:   "uuid,nmae\n,Rob Pike\n",
+			},
+			expected: `line 2: unknown column "nmae"`,
+		},
+		{
+			scenario: "short csv row",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatCSV,
+				data:   "uuid,name\n,Rob Pike\nx\n",
+			},
+			expected: "record on line 3: wrong number of fields",
+		},
+		{
+			scenario: "json number",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatJSON,
+				data:   "[\n{\"name\": 1}\n]",
+			},
+			expected: "line 2: name must be a string",
+		},
+		{
+			scenario: "json object",
+			input: struct {
+				format Format
+				data   string
+			}{
+				format: FormatJSON,
+				data:   `{"name": "Rob Pike"}`,
+			},
+			expected: "want a JSON array of objects",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			_, err := readRecords(strings.NewReader(tt.input.data), tt.input.format, []string{"uuid", "name"})
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package memdb
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func compareAuthorBooks(a, b sqlc.AuthorBook) int {
+	return cmp.Or(compareUUID(a.AuthorUuid, b.AuthorUuid), compareUUID(a.BookUuid, b.BookUuid))
+}
+
+// CreateAuthorBook checks the author and the book before the primary key,
+// as InnoDB does. Soft deleted rows still satisfy the foreign keys.
+func (db *DB) CreateAuthorBook(ctx context.Context, arg sqlc.CreateAuthorBookParams) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.authors[arg.AuthorUuid]; !ok {
+		return errNoReferencedRow(fkAuthorBooksAuthor)
+	}
+	if _, ok := db.books[arg.BookUuid]; !ok {
+		return errNoReferencedRow(fkAuthorBooksBook)
+	}
+	key := authorBookKey{author: arg.AuthorUuid, book: arg.BookUuid}
+	if _, ok := db.authorBooks[key]; ok {
+		return errDuplicate(arg.AuthorUuid.String()+"-"+arg.BookUuid.String(), "author_books")
+	}
+	now := now()
+	db.authorBooks[key] = sqlc.AuthorBook{
+		AuthorUuid: arg.AuthorUuid,
+		BookUuid:   arg.BookUuid,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	return nil
+}
+
+func (db *DB) DeleteAuthorBook(ctx context.Context, arg sqlc.DeleteAuthorBookParams) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	key := authorBookKey{author: arg.AuthorUuid, book: arg.BookUuid}
+	if _, ok := db.authorBooks[key]; !ok {
+		return 0, nil
+	}
+	delete(db.authorBooks, key)
+	return 1, nil
+}
+
+func (db *DB) DeleteAuthorBooksByAuthor(ctx context.Context, authorUuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.deleteAuthorBooks(func(key authorBookKey) bool {
+		return key.author == authorUuid
+	}), nil
+}
+
+func (db *DB) GetAuthorBook(ctx context.Context, arg sqlc.GetAuthorBookParams) (sqlc.AuthorBook, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	authorBook, ok := db.authorBooks[authorBookKey{author: arg.AuthorUuid, book: arg.BookUuid}]
+	if !ok || db.authors[arg.AuthorUuid].DeletedAt.Valid || db.books[arg.BookUuid].DeletedAt.Valid {
+		return sqlc.AuthorBook{}, sql.ErrNoRows
+	}
+	return authorBook, nil
+}
+
+// live returns the links whose author and book are not deleted and that
+// satisfy keep, ordered by author and book.
+func (db *DB) live(keep func(sqlc.AuthorBook) bool) []sqlc.AuthorBook {
+	return sorted(db.authorBooks, func(ab sqlc.AuthorBook) bool {
+		return !db.authors[ab.AuthorUuid].DeletedAt.Valid &&
+			!db.books[ab.BookUuid].DeletedAt.Valid &&
+			keep(ab)
+	}, compareAuthorBooks)
+}
+
+func (db *DB) ListAuthorBooks(ctx context.Context) ([]sqlc.ListAuthorBooksRow, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var rows []sqlc.ListAuthorBooksRow
+	for _, ab := range db.live(func(sqlc.AuthorBook) bool { return true }) {
+		author, book := db.authors[ab.AuthorUuid], db.books[ab.BookUuid]
+		rows = append(rows, sqlc.ListAuthorBooksRow{
+			AuthorUuid: author.Uuid,
+			AuthorName: author.Name,
+			AuthorBio:  author.Bio,
+			BookUuid:   book.Uuid,
+			BookTitle:  book.Title,
+		})
+	}
+	return rows, nil
+}
+
+func (db *DB) ListAuthorBooksPage(ctx context.Context, arg sqlc.ListAuthorBooksPageParams) ([]sqlc.ListAuthorBooksPageRow, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var rows []sqlc.ListAuthorBooksPageRow
+	for _, ab := range limit(db.live(func(ab sqlc.AuthorBook) bool {
+		after := cmp.Or(compareUUID(ab.AuthorUuid, arg.AfterAuthorUuid), compareUUID(ab.BookUuid, arg.AfterBookUuid))
+		return after > 0 && inRange(ab.CreatedAt, arg.CreatedFrom, arg.CreatedTo)
+	}), arg.Limit) {
+		author, book := db.authors[ab.AuthorUuid], db.books[ab.BookUuid]
+		rows = append(rows, sqlc.ListAuthorBooksPageRow{
+			AuthorUuid: author.Uuid,
+			AuthorName: author.Name,
+			AuthorBio:  author.Bio,
+			BookUuid:   book.Uuid,
+			BookTitle:  book.Title,
+		})
+	}
+	return rows, nil
+}
+
+func (db *DB) ListAuthorNamesOfBooks(ctx context.Context, bookUuids []binuuid.UUID) ([]sqlc.ListAuthorNamesOfBooksRow, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	type row struct {
+		sqlc.ListAuthorNamesOfBooksRow
+		authorUuid binuuid.UUID
+	}
+	var found []row
+	for key := range db.authorBooks {
+		author := db.authors[key.author]
+		if !slices.Contains(bookUuids, key.book) || author.DeletedAt.Valid {
+			continue
+		}
+		found = append(found, row{
+			ListAuthorNamesOfBooksRow: sqlc.ListAuthorNamesOfBooksRow{
+				BookUuid:   key.book,
+				AuthorName: author.Name,
+			},
+			authorUuid: author.Uuid,
+		})
+	}
+	slices.SortFunc(found, func(a, b row) int {
+		return cmp.Or(
+			compareUUID(a.BookUuid, b.BookUuid),
+			cmp.Compare(a.AuthorName, b.AuthorName),
+			compareUUID(a.authorUuid, b.authorUuid),
+		)
+	})
+
+	var rows []sqlc.ListAuthorNamesOfBooksRow
+	for _, r := range found {
+		rows = append(rows, r.ListAuthorNamesOfBooksRow)
+	}
+	return rows, nil
+}
+
+func (db *DB) ListAuthorsByBook(ctx context.Context, arg sqlc.ListAuthorsByBookParams) ([]sqlc.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// the book may be deleted, only its authors are filtered
+	var authors []sqlc.Author
+	for key := range db.authorBooks {
+		author := db.authors[key.author]
+		if key.book == arg.BookUuid && compareUUID(author.Uuid, arg.AfterUuid) > 0 && !author.DeletedAt.Valid {
+			authors = append(authors, author)
+		}
+	}
+	slices.SortFunc(authors, compareAuthors)
+	return limit(authors, arg.Limit), nil
+}
+
+func (db *DB) ListBooksByAuthor(ctx context.Context, arg sqlc.ListBooksByAuthorParams) ([]sqlc.Book, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	// the author may be deleted, only their books are filtered
+	var books []sqlc.Book
+	for key := range db.authorBooks {
+		book := db.books[key.book]
+		if key.author == arg.AuthorUuid && compareUUID(book.Uuid, arg.AfterUuid) > 0 && !book.DeletedAt.Valid {
+			books = append(books, book)
+		}
+	}
+	slices.SortFunc(books, compareBooks)
+	return limit(books, arg.Limit), nil
+}
+
+func (db *DB) PurgeAuthorBooksOfAuthors(ctx context.Context, before time.Time) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.deleteAuthorBooks(func(key authorBookKey) bool {
+		return deletedBefore(db.authors[key.author].DeletedAt, before)
+	}), nil
+}
+
+func (db *DB) PurgeAuthorBooksOfBooks(ctx context.Context, before time.Time) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return db.deleteAuthorBooks(func(key authorBookKey) bool {
+		return deletedBefore(db.books[key.book].DeletedAt, before)
+	}), nil
+}
+
+// deleteAuthorBooks deletes the links that satisfy match and returns how
+// many there were.
+func (db *DB) deleteAuthorBooks(match func(authorBookKey) bool) int64 {
+	var n int64
+	for key := range db.authorBooks {
+		if match(key) {
+			delete(db.authorBooks, key)
+			n++
+		}
+	}
+	return n
+}
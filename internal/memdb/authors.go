@@ -0,0 +1,159 @@
+package memdb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func compareAuthors(a, b sqlc.Author) int {
+	return compareUUID(a.Uuid, b.Uuid)
+}
+
+func (db *DB) CreateAuthor(ctx context.Context, arg sqlc.CreateAuthorParams) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.authors[arg.Uuid]; ok {
+		return errDuplicate(arg.Uuid.String(), "authors")
+	}
+	now := now()
+	db.authors[arg.Uuid] = sqlc.Author{
+		Name:      arg.Name,
+		Bio:       arg.Bio,
+		Uuid:      arg.Uuid,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+	return nil
+}
+
+func (db *DB) DeleteAuthor(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	author, ok := db.authors[uuid]
+	if !ok || author.DeletedAt.Valid {
+		return 0, nil
+	}
+	now := now()
+	author.DeletedAt = sql.NullTime{Time: now, Valid: true}
+	author.Version++
+	author.UpdatedAt = now
+	db.authors[uuid] = author
+	return 1, nil
+}
+
+func (db *DB) GetAuthor(ctx context.Context, uuid binuuid.UUID) (sqlc.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	author, ok := db.authors[uuid]
+	if !ok || author.DeletedAt.Valid {
+		return sqlc.Author{}, sql.ErrNoRows
+	}
+	return author, nil
+}
+
+func (db *DB) GetAuthorIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (sqlc.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	author, ok := db.authors[uuid]
+	if !ok {
+		return sqlc.Author{}, sql.ErrNoRows
+	}
+	return author, nil
+}
+
+func (db *DB) ListAuthors(ctx context.Context) ([]sqlc.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return sorted(db.authors, func(a sqlc.Author) bool {
+		return !a.DeletedAt.Valid
+	}, compareAuthors), nil
+}
+
+func (db *DB) ListAuthorsPage(ctx context.Context, arg sqlc.ListAuthorsPageParams) ([]sqlc.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.authors, func(a sqlc.Author) bool {
+		return compareUUID(a.Uuid, arg.AfterUuid) > 0 &&
+			inRange(a.CreatedAt, arg.CreatedFrom, arg.CreatedTo) &&
+			inRange(a.UpdatedAt, arg.UpdatedFrom, arg.UpdatedTo) &&
+			!a.DeletedAt.Valid
+	}, compareAuthors), arg.Limit), nil
+}
+
+func (db *DB) ListAuthorsPageIncludingDeleted(ctx context.Context, arg sqlc.ListAuthorsPageIncludingDeletedParams) ([]sqlc.Author, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.authors, func(a sqlc.Author) bool {
+		return compareUUID(a.Uuid, arg.AfterUuid) > 0 &&
+			inRange(a.CreatedAt, arg.CreatedFrom, arg.CreatedTo) &&
+			inRange(a.UpdatedAt, arg.UpdatedFrom, arg.UpdatedTo)
+	}, compareAuthors), arg.Limit), nil
+}
+
+// PurgeAuthors fails without deleting anything when a purgeable author
+// still has links, like the single DELETE statement it replaces.
+func (db *DB) PurgeAuthors(ctx context.Context, before time.Time) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var purge []binuuid.UUID
+	for uuid, author := range db.authors {
+		if deletedBefore(author.DeletedAt, before) {
+			purge = append(purge, uuid)
+		}
+	}
+	for _, uuid := range purge {
+		for key := range db.authorBooks {
+			if key.author == uuid {
+				return 0, errRowIsReferenced(fkAuthorBooksAuthor)
+			}
+		}
+	}
+	for _, uuid := range purge {
+		delete(db.authors, uuid)
+	}
+	return int64(len(purge)), nil
+}
+
+func (db *DB) RestoreAuthor(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	author, ok := db.authors[uuid]
+	if !ok || !author.DeletedAt.Valid {
+		return 0, nil
+	}
+	author.DeletedAt = sql.NullTime{}
+	author.Version++
+	author.UpdatedAt = now()
+	db.authors[uuid] = author
+	return 1, nil
+}
+
+func (db *DB) UpdateAuthor(ctx context.Context, arg sqlc.UpdateAuthorParams) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	author, ok := db.authors[arg.Uuid]
+	if !ok || author.Version != arg.Version || author.DeletedAt.Valid {
+		return 0, nil
+	}
+	author.Name = arg.Name
+	author.Bio = arg.Bio
+	author.Version++
+	author.UpdatedAt = now()
+	db.authors[arg.Uuid] = author
+	return 1, nil
+}
@@ -0,0 +1,234 @@
+package memdb
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func compareBooks(a, b sqlc.Book) int {
+	return compareUUID(a.Uuid, b.Uuid)
+}
+
+// CreateBook checks the publisher before the primary key, as InnoDB does.
+// A soft deleted publisher still satisfies the foreign key.
+func (db *DB) CreateBook(ctx context.Context, arg sqlc.CreateBookParams) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.publishers[arg.PublisherUuid]; !ok {
+		return errNoReferencedRow(fkBooksPublisher)
+	}
+	if _, ok := db.books[arg.Uuid]; ok {
+		return errDuplicate(arg.Uuid.String(), "books")
+	}
+	now := now()
+	db.books[arg.Uuid] = sqlc.Book{
+		Title:         arg.Title,
+		Uuid:          arg.Uuid,
+		PublisherUuid: arg.PublisherUuid,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		Version:       1,
+	}
+	return nil
+}
+
+func (db *DB) DeleteBook(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	book, ok := db.books[uuid]
+	if !ok || book.DeletedAt.Valid {
+		return 0, nil
+	}
+	now := now()
+	book.DeletedAt = sql.NullTime{Time: now, Valid: true}
+	book.Version++
+	book.UpdatedAt = now
+	db.books[uuid] = book
+	return 1, nil
+}
+
+func (db *DB) GetBook(ctx context.Context, uuid binuuid.UUID) (sqlc.Book, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	book, ok := db.books[uuid]
+	if !ok || book.DeletedAt.Valid {
+		return sqlc.Book{}, sql.ErrNoRows
+	}
+	return book, nil
+}
+
+func (db *DB) GetBookIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (sqlc.Book, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	book, ok := db.books[uuid]
+	if !ok {
+		return sqlc.Book{}, sql.ErrNoRows
+	}
+	return book, nil
+}
+
+func (db *DB) GetBookPublisher(ctx context.Context, uuid binuuid.UUID) (sqlc.GetBookPublisherRow, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	book, ok := db.books[uuid]
+	if !ok || book.DeletedAt.Valid {
+		return sqlc.GetBookPublisherRow{}, sql.ErrNoRows
+	}
+	publisher := db.publishers[book.PublisherUuid]
+	if publisher.DeletedAt.Valid {
+		return sqlc.GetBookPublisherRow{}, sql.ErrNoRows
+	}
+	return sqlc.GetBookPublisherRow{
+		BookUuid:      book.Uuid,
+		BookTitle:     book.Title,
+		PublisherUuid: publisher.Uuid,
+		PublisherName: publisher.Name,
+	}, nil
+}
+
+func (db *DB) ListBookSummariesPage(ctx context.Context, arg sqlc.ListBookSummariesPageParams) ([]sqlc.ListBookSummariesPageRow, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var rows []sqlc.ListBookSummariesPageRow
+	for _, book := range limit(sorted(db.books, func(b sqlc.Book) bool {
+		return compareUUID(b.Uuid, arg.AfterUuid) > 0 && !b.DeletedAt.Valid
+	}, compareBooks), arg.Limit) {
+		rows = append(rows, sqlc.ListBookSummariesPageRow{
+			Title:         book.Title,
+			Uuid:          book.Uuid,
+			PublisherUuid: book.PublisherUuid,
+			CreatedAt:     book.CreatedAt,
+			UpdatedAt:     book.UpdatedAt,
+			DeletedAt:     book.DeletedAt,
+			Version:       book.Version,
+			PublisherName: db.publishers[book.PublisherUuid].Name,
+		})
+	}
+	return rows, nil
+}
+
+func (db *DB) ListBooks(ctx context.Context) ([]sqlc.Book, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return sorted(db.books, func(b sqlc.Book) bool {
+		return !b.DeletedAt.Valid
+	}, compareBooks), nil
+}
+
+func (db *DB) ListBooksPage(ctx context.Context, arg sqlc.ListBooksPageParams) ([]sqlc.Book, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.books, func(b sqlc.Book) bool {
+		return compareUUID(b.Uuid, arg.AfterUuid) > 0 &&
+			inRange(b.CreatedAt, arg.CreatedFrom, arg.CreatedTo) &&
+			inRange(b.UpdatedAt, arg.UpdatedFrom, arg.UpdatedTo) &&
+			!b.DeletedAt.Valid
+	}, compareBooks), arg.Limit), nil
+}
+
+func (db *DB) ListBooksPageIncludingDeleted(ctx context.Context, arg sqlc.ListBooksPageIncludingDeletedParams) ([]sqlc.Book, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.books, func(b sqlc.Book) bool {
+		return compareUUID(b.Uuid, arg.AfterUuid) > 0 &&
+			inRange(b.CreatedAt, arg.CreatedFrom, arg.CreatedTo) &&
+			inRange(b.UpdatedAt, arg.UpdatedFrom, arg.UpdatedTo)
+	}, compareBooks), arg.Limit), nil
+}
+
+// PurgeBooks fails without deleting anything when a purgeable book still
+// has links.
+func (db *DB) PurgeBooks(ctx context.Context, before time.Time) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var purge []binuuid.UUID
+	for uuid, book := range db.books {
+		if deletedBefore(book.DeletedAt, before) {
+			purge = append(purge, uuid)
+		}
+	}
+	for _, uuid := range purge {
+		for key := range db.authorBooks {
+			if key.book == uuid {
+				return 0, errRowIsReferenced(fkAuthorBooksBook)
+			}
+		}
+	}
+	for _, uuid := range purge {
+		delete(db.books, uuid)
+	}
+	return int64(len(purge)), nil
+}
+
+// ReassignBooks moves deleted books too. The new publisher is only checked
+// when there is a book to move.
+func (db *DB) ReassignBooks(ctx context.Context, arg sqlc.ReassignBooksParams) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var move []binuuid.UUID
+	for uuid, book := range db.books {
+		if book.PublisherUuid == arg.FromPublisherUuid {
+			move = append(move, uuid)
+		}
+	}
+	if len(move) == 0 {
+		return 0, nil
+	}
+	if _, ok := db.publishers[arg.ToPublisherUuid]; !ok {
+		return 0, errNoReferencedRow(fkBooksPublisher)
+	}
+	now := now()
+	for _, uuid := range move {
+		book := db.books[uuid]
+		book.PublisherUuid = arg.ToPublisherUuid
+		book.Version++
+		book.UpdatedAt = now
+		db.books[uuid] = book
+	}
+	return int64(len(move)), nil
+}
+
+func (db *DB) RestoreBook(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	book, ok := db.books[uuid]
+	if !ok || !book.DeletedAt.Valid {
+		return 0, nil
+	}
+	book.DeletedAt = sql.NullTime{}
+	book.Version++
+	book.UpdatedAt = now()
+	db.books[uuid] = book
+	return 1, nil
+}
+
+func (db *DB) UpdateBook(ctx context.Context, arg sqlc.UpdateBookParams) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	book, ok := db.books[arg.Uuid]
+	if !ok || book.Version != arg.Version || book.DeletedAt.Valid {
+		return 0, nil
+	}
+	book.Title = arg.Title
+	book.Version++
+	book.UpdatedAt = now()
+	db.books[arg.Uuid] = book
+	return 1, nil
+}
@@ -0,0 +1,133 @@
+// Package memdb is an in-memory implementation of sqlc.Querier for unit
+// tests that should not need a MySQL server.
+//
+// DB enforces the primary keys and foreign keys of db/migrations and fails
+// the way MySQL does, with a *mysql.MySQLError that dberr.Translate maps to
+// the same domain error: creating a book for an unknown publisher_uuid
+// returns error 1452, dberr.ErrReferenceMissing. Package querytest runs the
+// same checks against DB and MySQL so that the two cannot drift apart.
+//
+// Differences tests must not depend on:
+//   - There are no transactions; every call takes effect immediately.
+//   - Names sort byte by byte, while MySQL's collation ignores case and
+//     accents. ListPublishersByName ignores case only.
+package memdb
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/go-sql-driver/mysql"
+)
+
+// MySQL server error codes returned by DB, see package dberr.
+const (
+	codeDupEntry         = 1062
+	codeRowIsReferenced2 = 1451
+	codeNoReferencedRow2 = 1452
+)
+
+// DB holds the four tables of the schema. The zero value is not usable,
+// call New. A DB is safe for concurrent use.
+type DB struct {
+	mu          sync.Mutex
+	authors     map[binuuid.UUID]sqlc.Author
+	publishers  map[binuuid.UUID]sqlc.Publisher
+	books       map[binuuid.UUID]sqlc.Book
+	authorBooks map[authorBookKey]sqlc.AuthorBook
+}
+
+var _ sqlc.Querier = (*DB)(nil)
+
+type authorBookKey struct {
+	author binuuid.UUID
+	book   binuuid.UUID
+}
+
+// New returns an empty database.
+func New() *DB {
+	return &DB{
+		authors:     make(map[binuuid.UUID]sqlc.Author),
+		publishers:  make(map[binuuid.UUID]sqlc.Publisher),
+		books:       make(map[binuuid.UUID]sqlc.Book),
+		authorBooks: make(map[authorBookKey]sqlc.AuthorBook),
+	}
+}
+
+// now returns the value of CURRENT_TIMESTAMP(6) on a UTC connection.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// deletedBefore reports whether a row was purgeable with
+// deleted_at < deletedBefore.
+func deletedBefore(deletedAt sql.NullTime, before time.Time) bool {
+	return deletedAt.Valid && deletedAt.Time.Before(before)
+}
+
+// inRange reports whether from <= t < to.
+func inRange(t, from, to time.Time) bool {
+	return !t.Before(from) && t.Before(to)
+}
+
+func compareUUID(a, b binuuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// sorted returns the rows of table that satisfy keep, ordered by cmp. Like
+// the generated code it returns nil rather than an empty slice.
+func sorted[K comparable, V any](table map[K]V, keep func(V) bool, cmp func(a, b V) int) []V {
+	var rows []V
+	for _, row := range table {
+		if keep(row) {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, cmp)
+	return rows
+}
+
+// limit applies LIMIT n to rows.
+func limit[T any](rows []T, n int32) []T {
+	if int(n) >= len(rows) {
+		return rows
+	}
+	if n <= 0 {
+		return nil
+	}
+	return rows[:n]
+}
+
+func errDuplicate(key string, table string) error {
+	return &mysql.MySQLError{
+		Number:  codeDupEntry,
+		Message: fmt.Sprintf("Duplicate entry '%s' for key '%s.PRIMARY'", key, table),
+	}
+}
+
+func errNoReferencedRow(constraint string) error {
+	return &mysql.MySQLError{
+		Number:  codeNoReferencedRow2,
+		Message: fmt.Sprintf("Cannot add or update a child row: a foreign key constraint fails (%s)", constraint),
+	}
+}
+
+func errRowIsReferenced(constraint string) error {
+	return &mysql.MySQLError{
+		Number:  codeRowIsReferenced2,
+		Message: fmt.Sprintf("Cannot delete or update a parent row: a foreign key constraint fails (%s)", constraint),
+	}
+}
+
+// Foreign keys as named by migration 000005.
+const (
+	fkBooksPublisher    = "`books`, CONSTRAINT `books_ibfk_1` FOREIGN KEY (`publisher_uuid`) REFERENCES `publishers` (`uuid`)"
+	fkAuthorBooksAuthor = "`author_books`, CONSTRAINT `author_books_ibfk_1` FOREIGN KEY (`author_uuid`) REFERENCES `authors` (`uuid`)"
+	fkAuthorBooksBook   = "`author_books`, CONSTRAINT `author_books_ibfk_2` FOREIGN KEY (`book_uuid`) REFERENCES `books` (`uuid`)"
+)
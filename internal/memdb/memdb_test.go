@@ -0,0 +1,14 @@
+package memdb
+
+import (
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/querytest"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func TestConformance(t *testing.T) {
+	querytest.Run(t, func(t *testing.T) sqlc.Querier {
+		return New()
+	})
+}
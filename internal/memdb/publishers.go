@@ -0,0 +1,196 @@
+package memdb
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func comparePublishers(a, b sqlc.Publisher) int {
+	return compareUUID(a.Uuid, b.Uuid)
+}
+
+func (db *DB) CountBooksByPublisher(ctx context.Context, publisherUuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	var n int64
+	for _, book := range db.books {
+		if book.PublisherUuid == publisherUuid && !book.DeletedAt.Valid {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (db *DB) CreatePublisher(ctx context.Context, arg sqlc.CreatePublisherParams) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	if _, ok := db.publishers[arg.Uuid]; ok {
+		return errDuplicate(arg.Uuid.String(), "publishers")
+	}
+	now := now()
+	db.publishers[arg.Uuid] = sqlc.Publisher{
+		Name:      arg.Name,
+		Uuid:      arg.Uuid,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+	return nil
+}
+
+func (db *DB) DeletePublisher(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	publisher, ok := db.publishers[uuid]
+	if !ok || publisher.DeletedAt.Valid {
+		return 0, nil
+	}
+	now := now()
+	publisher.DeletedAt = sql.NullTime{Time: now, Valid: true}
+	publisher.Version++
+	publisher.UpdatedAt = now
+	db.publishers[uuid] = publisher
+	return 1, nil
+}
+
+func (db *DB) GetPublisher(ctx context.Context, uuid binuuid.UUID) (sqlc.Publisher, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	publisher, ok := db.publishers[uuid]
+	if !ok || publisher.DeletedAt.Valid {
+		return sqlc.Publisher{}, sql.ErrNoRows
+	}
+	return publisher, nil
+}
+
+func (db *DB) GetPublisherBooks(ctx context.Context, uuid binuuid.UUID) ([]sqlc.GetPublisherBooksRow, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	publisher, ok := db.publishers[uuid]
+	if !ok || publisher.DeletedAt.Valid {
+		return nil, nil
+	}
+	var rows []sqlc.GetPublisherBooksRow
+	for _, book := range sorted(db.books, func(b sqlc.Book) bool {
+		return b.PublisherUuid == uuid && !b.DeletedAt.Valid
+	}, compareBooks) {
+		rows = append(rows, sqlc.GetPublisherBooksRow{
+			PublisherUuid: publisher.Uuid,
+			PublisherName: publisher.Name,
+			BookUuid:      book.Uuid,
+			BookTitle:     book.Title,
+		})
+	}
+	return rows, nil
+}
+
+func (db *DB) GetPublisherIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (sqlc.Publisher, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	publisher, ok := db.publishers[uuid]
+	if !ok {
+		return sqlc.Publisher{}, sql.ErrNoRows
+	}
+	return publisher, nil
+}
+
+func (db *DB) ListPublishers(ctx context.Context) ([]sqlc.Publisher, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return sorted(db.publishers, func(p sqlc.Publisher) bool {
+		return !p.DeletedAt.Valid
+	}, comparePublishers), nil
+}
+
+func (db *DB) ListPublishersByName(ctx context.Context, name string) ([]sqlc.Publisher, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.publishers, func(p sqlc.Publisher) bool {
+		return strings.EqualFold(p.Name, name) && !p.DeletedAt.Valid
+	}, comparePublishers), 2), nil
+}
+
+func (db *DB) ListPublishersPage(ctx context.Context, arg sqlc.ListPublishersPageParams) ([]sqlc.Publisher, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.publishers, func(p sqlc.Publisher) bool {
+		return compareUUID(p.Uuid, arg.AfterUuid) > 0 &&
+			inRange(p.CreatedAt, arg.CreatedFrom, arg.CreatedTo) &&
+			inRange(p.UpdatedAt, arg.UpdatedFrom, arg.UpdatedTo) &&
+			!p.DeletedAt.Valid
+	}, comparePublishers), arg.Limit), nil
+}
+
+func (db *DB) ListPublishersPageIncludingDeleted(ctx context.Context, arg sqlc.ListPublishersPageIncludingDeletedParams) ([]sqlc.Publisher, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	return limit(sorted(db.publishers, func(p sqlc.Publisher) bool {
+		return compareUUID(p.Uuid, arg.AfterUuid) > 0 &&
+			inRange(p.CreatedAt, arg.CreatedFrom, arg.CreatedTo) &&
+			inRange(p.UpdatedAt, arg.UpdatedFrom, arg.UpdatedTo)
+	}, comparePublishers), arg.Limit), nil
+}
+
+// PurgePublishers skips publishers that still have books, deleted or not.
+func (db *DB) PurgePublishers(ctx context.Context, before time.Time) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	inUse := make(map[binuuid.UUID]bool)
+	for _, book := range db.books {
+		inUse[book.PublisherUuid] = true
+	}
+	var n int64
+	for uuid, publisher := range db.publishers {
+		if deletedBefore(publisher.DeletedAt, before) && !inUse[uuid] {
+			delete(db.publishers, uuid)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (db *DB) RestorePublisher(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	publisher, ok := db.publishers[uuid]
+	if !ok || !publisher.DeletedAt.Valid {
+		return 0, nil
+	}
+	publisher.DeletedAt = sql.NullTime{}
+	publisher.Version++
+	publisher.UpdatedAt = now()
+	db.publishers[uuid] = publisher
+	return 1, nil
+}
+
+func (db *DB) UpdatePublisher(ctx context.Context, arg sqlc.UpdatePublisherParams) (int64, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	publisher, ok := db.publishers[arg.Uuid]
+	if !ok || publisher.Version != arg.Version || publisher.DeletedAt.Valid {
+		return 0, nil
+	}
+	publisher.Name = arg.Name
+	publisher.Version++
+	publisher.UpdatedAt = now()
+	db.publishers[arg.Uuid] = publisher
+	return 1, nil
+}
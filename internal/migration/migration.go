@@ -0,0 +1,153 @@
+// Package migration applies the schema migrations embedded in package
+// db/migrations with golang-migrate.
+//
+// The connection must allow multiple statements per query, see
+// config.MySQL.MultiStatements, because some migration files hold several.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/db/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+type Migrator struct {
+	migrate *migrate.Migrate
+	source  source.Driver
+}
+
+// New returns a migrator for the database db is connected to. It holds one
+// connection of db until Close is called; db itself is left open.
+func New(ctx context.Context, db *sql.DB) (*Migrator, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := mysql.WithConnection(ctx, conn, &mysql.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("open migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "mysql", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	return &Migrator{migrate: m, source: src}, nil
+}
+
+// SetLogger reports every applied migration to logf.
+func (m *Migrator) SetLogger(logf func(format string, v ...any)) {
+	m.migrate.Log = logger(logf)
+}
+
+type logger func(format string, v ...any)
+
+func (l logger) Printf(format string, v ...any) {
+	l(format, v...)
+}
+
+func (l logger) Verbose() bool {
+	return false
+}
+
+// Up applies every pending migration. It is not an error when there is
+// none.
+func (m *Migrator) Up() error {
+	return ignoreNoChange(m.migrate.Up())
+}
+
+// Down reverts the last n applied migrations.
+func (m *Migrator) Down(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("down: want a positive number of migrations, got %d", n)
+	}
+	return ignoreNoChange(m.migrate.Steps(-n))
+}
+
+// Goto migrates up or down to version.
+func (m *Migrator) Goto(version uint) error {
+	return ignoreNoChange(m.migrate.Migrate(version))
+}
+
+// Force sets the version without running any migration and clears the dirty
+// flag left by a failed migration. A version of -1 means no migration.
+func (m *Migrator) Force(version int) error {
+	return m.migrate.Force(version)
+}
+
+// Status describes the migrations of a database.
+type Status struct {
+	// Version is the last applied migration, 0 if none.
+	Version uint
+	// Dirty reports that migration Version failed halfway and has to be
+	// fixed by hand and forced.
+	Dirty      bool
+	Migrations []Migration
+}
+
+type Migration struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// Status returns the applied version and every embedded migration.
+func (m *Migrator) Status() (Status, error) {
+	var status Status
+
+	version, dirty, err := m.migrate.Version()
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+	case err != nil:
+		return Status{}, err
+	default:
+		status.Version, status.Dirty = version, dirty
+	}
+
+	v, err := m.source.First()
+	for err == nil {
+		r, name, rerr := m.source.ReadUp(v)
+		if rerr != nil {
+			return Status{}, rerr
+		}
+		r.Close()
+		status.Migrations = append(status.Migrations, Migration{
+			Version: v,
+			Name:    name,
+			Applied: v <= status.Version && !(status.Dirty && v == status.Version),
+		})
+		v, err = m.source.Next(v)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return Status{}, err
+	}
+	return status, nil
+}
+
+// Close releases the connection held by m.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.migrate.Close()
+	return errors.Join(srcErr, dbErr)
+}
+
+func ignoreNoChange(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	return err
+}
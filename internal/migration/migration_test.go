@@ -0,0 +1,43 @@
+package migration
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/db/migrations"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := uint(1)
+	v, err := src.First()
+	for err == nil {
+		if v != want {
+			t.Errorf("got=%v, want=%v", v, want)
+		}
+		if up, _, rerr := src.ReadUp(v); rerr != nil {
+			t.Errorf("migration %d: %v", v, rerr)
+		} else {
+			up.Close()
+		}
+		if down, _, rerr := src.ReadDown(v); rerr != nil {
+			t.Errorf("migration %d: %v", v, rerr)
+		} else {
+			down.Close()
+		}
+		want = v + 1
+		v, err = src.Next(v)
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal(err)
+	}
+	if want == 1 {
+		t.Error("no migrations embedded")
+	}
+}
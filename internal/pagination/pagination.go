@@ -0,0 +1,157 @@
+// Package pagination implements keyset pagination over uuid-ordered lists.
+//
+// A cursor is the URL-safe, unpadded base64 encoding of a version byte
+// followed by the 16-byte form of every key of the last row seen. The
+// format is stable, so cursors can be passed through HTTP and CLI layers
+// unchanged.
+//
+// Lists that are not ordered by uuid, such as search results ranked by
+// relevance, use offset cursors instead: a different version byte followed
+// by the 8-byte big-endian offset of the next row.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 1000
+
+	cursorVersion       = 1
+	offsetCursorVersion = 2
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type Request struct {
+	// Limit is the page size. Zero selects DefaultLimit and values above
+	// MaxLimit are clamped.
+	Limit int
+	// Cursor is the NextCursor of the previous page, empty for the first page.
+	Cursor string
+}
+
+type Page[T any] struct {
+	Items []T
+	// NextCursor is empty on the last page.
+	NextCursor string
+}
+
+// PageLimit returns the normalized page size of r.
+func (r Request) PageLimit() int {
+	switch {
+	case r.Limit <= 0:
+		return DefaultLimit
+	case r.Limit > MaxLimit:
+		return MaxLimit
+	default:
+		return r.Limit
+	}
+}
+
+// QueryLimit returns the LIMIT to pass to a page query. It is one more than
+// the page size so that NewPage can tell whether another page follows.
+func (r Request) QueryLimit() int32 {
+	return int32(r.PageLimit() + 1)
+}
+
+// After decodes the cursor of r into n keys. The first page starts after
+// binuuid.Nil.
+func (r Request) After(n int) ([]binuuid.UUID, error) {
+	if r.Cursor == "" {
+		return make([]binuuid.UUID, n), nil
+	}
+	return DecodeCursor(r.Cursor, n)
+}
+
+// NewPage trims rows fetched with r.QueryLimit to the page size and derives
+// the next cursor from the keys of the last row kept.
+func NewPage[T any](r Request, rows []T, keys func(T) []binuuid.UUID) Page[T] {
+	limit := r.PageLimit()
+	if len(rows) <= limit {
+		return Page[T]{Items: rows}
+	}
+	rows = rows[:limit]
+	return Page[T]{
+		Items:      rows,
+		NextCursor: EncodeCursor(keys(rows[len(rows)-1])...),
+	}
+}
+
+func EncodeCursor(keys ...binuuid.UUID) string {
+	buf := make([]byte, 0, 1+len(keys)*16)
+	buf = append(buf, cursorVersion)
+	for _, key := range keys {
+		buf = append(buf, key[:]...)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// DecodeCursor decodes a cursor holding exactly n keys.
+func DecodeCursor(cursor string, n int) ([]binuuid.UUID, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if len(buf) != 1+n*16 || buf[0] != cursorVersion {
+		return nil, ErrInvalidCursor
+	}
+
+	keys := make([]binuuid.UUID, n)
+	for i := range keys {
+		copy(keys[i][:], buf[1+i*16:])
+	}
+	return keys, nil
+}
+
+// Offset decodes the cursor of r as an offset cursor. The first page starts
+// at offset 0.
+func (r Request) Offset() (int, error) {
+	if r.Cursor == "" {
+		return 0, nil
+	}
+	return DecodeOffsetCursor(r.Cursor)
+}
+
+// NewOffsetPage is NewPage for rows fetched with r.QueryLimit starting at
+// offset.
+func NewOffsetPage[T any](r Request, offset int, rows []T) Page[T] {
+	limit := r.PageLimit()
+	if len(rows) <= limit {
+		return Page[T]{Items: rows}
+	}
+	return Page[T]{
+		Items:      rows[:limit],
+		NextCursor: EncodeOffsetCursor(offset + limit),
+	}
+}
+
+func EncodeOffsetCursor(offset int) string {
+	buf := make([]byte, 1, 9)
+	buf[0] = offsetCursorVersion
+	buf = binary.BigEndian.AppendUint64(buf, uint64(offset))
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func DecodeOffsetCursor(cursor string) (int, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if len(buf) != 9 || buf[0] != offsetCursorVersion {
+		return 0, ErrInvalidCursor
+	}
+
+	offset := binary.BigEndian.Uint64(buf[1:])
+	if offset > math.MaxInt32 {
+		return 0, ErrInvalidCursor
+	}
+	return int(offset), nil
+}
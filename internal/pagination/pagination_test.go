@@ -0,0 +1,265 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    []binuuid.UUID
+		expected []binuuid.UUID
+	}{
+		{
+			scenario: "single key",
+			input:    []binuuid.UUID{binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10")},
+			expected: []binuuid.UUID{binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10")},
+		},
+		{
+			scenario: "composite key",
+			input: []binuuid.UUID{
+				binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+				binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b11"),
+			},
+			expected: []binuuid.UUID{
+				binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b10"),
+				binuuid.MustParse("0191d6c4-7f6e-7c1a-9a53-2f1d7e4f9b11"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			keys, err := DecodeCursor(EncodeCursor(tt.input...), len(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := range keys {
+				if keys[i] != tt.expected[i] {
+					t.Errorf("got=%v, want=%v", keys[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			cursor string
+			n      int
+		}
+		expected error
+	}{
+		{
+			scenario: "not base64",
+			input: struct {
+				cursor string
+				n      int
+			}{
+				cursor: "!!!",
+				n:      1,
+			},
+			expected: ErrInvalidCursor,
+		},
+		{
+			scenario: "wrong number of keys",
+			input: struct {
+				cursor string
+				n      int
+			}{
+				cursor: EncodeCursor(binuuid.New()),
+				n:      2,
+			},
+			expected: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			_, err := DecodeCursor(tt.input.cursor, tt.input.n)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	keys := []binuuid.UUID{binuuid.New(), binuuid.New(), binuuid.New()}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			req  Request
+			rows []binuuid.UUID
+		}
+		expected Page[binuuid.UUID]
+	}{
+		{
+			scenario: "more rows follow",
+			input: struct {
+				req  Request
+				rows []binuuid.UUID
+			}{
+				req:  Request{Limit: 2},
+				rows: keys,
+			},
+			expected: Page[binuuid.UUID]{
+				Items:      keys[:2],
+				NextCursor: EncodeCursor(keys[1]),
+			},
+		},
+		{
+			scenario: "last page",
+			input: struct {
+				req  Request
+				rows []binuuid.UUID
+			}{
+				req:  Request{Limit: 3},
+				rows: keys,
+			},
+			expected: Page[binuuid.UUID]{
+				Items: keys,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			page := NewPage(tt.input.req, tt.input.rows, func(key binuuid.UUID) []binuuid.UUID {
+				return []binuuid.UUID{key}
+			})
+
+			if len(page.Items) != len(tt.expected.Items) {
+				t.Fatalf("got=%v, want=%v", page.Items, tt.expected.Items)
+			}
+			for i := range page.Items {
+				if page.Items[i] != tt.expected.Items[i] {
+					t.Errorf("got=%v, want=%v", page.Items[i], tt.expected.Items[i])
+				}
+			}
+			if page.NextCursor != tt.expected.NextCursor {
+				t.Errorf("got=%v, want=%v", page.NextCursor, tt.expected.NextCursor)
+			}
+		})
+	}
+}
+
+func TestOffsetCursor(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    string
+		expected struct {
+			offset int
+			err    error
+		}
+	}{
+		{
+			scenario: "offset",
+			input:    EncodeOffsetCursor(100),
+			expected: struct {
+				offset int
+				err    error
+			}{
+				offset: 100,
+			},
+		},
+		{
+			scenario: "keyset cursor",
+			input:    EncodeCursor(binuuid.New()),
+			expected: struct {
+				offset int
+				err    error
+			}{
+				err: ErrInvalidCursor,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			offset, err := DecodeOffsetCursor(tt.input)
+			if !errors.Is(err, tt.expected.err) {
+				t.Fatalf("got=%v, want=%v", err, tt.expected.err)
+			}
+			if offset != tt.expected.offset {
+				t.Errorf("got=%v, want=%v", offset, tt.expected.offset)
+			}
+		})
+	}
+}
+
+func TestNewOffsetPage(t *testing.T) {
+	rows := []int{10, 11, 12}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			req    Request
+			offset int
+			rows   []int
+		}
+		expected Page[int]
+	}{
+		{
+			scenario: "more rows follow",
+			input: struct {
+				req    Request
+				offset int
+				rows   []int
+			}{
+				req:    Request{Limit: 2},
+				offset: 10,
+				rows:   rows,
+			},
+			expected: Page[int]{
+				Items:      rows[:2],
+				NextCursor: EncodeOffsetCursor(12),
+			},
+		},
+		{
+			scenario: "last page",
+			input: struct {
+				req    Request
+				offset int
+				rows   []int
+			}{
+				req:    Request{Limit: 3},
+				offset: 10,
+				rows:   rows,
+			},
+			expected: Page[int]{
+				Items: rows,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			page := NewOffsetPage(tt.input.req, tt.input.offset, tt.input.rows)
+
+			if len(page.Items) != len(tt.expected.Items) {
+				t.Fatalf("got=%v, want=%v", page.Items, tt.expected.Items)
+			}
+			for i := range page.Items {
+				if page.Items[i] != tt.expected.Items[i] {
+					t.Errorf("got=%v, want=%v", page.Items[i], tt.expected.Items[i])
+				}
+			}
+			if page.NextCursor != tt.expected.NextCursor {
+				t.Errorf("got=%v, want=%v", page.NextCursor, tt.expected.NextCursor)
+			}
+		})
+	}
+}
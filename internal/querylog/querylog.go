@@ -0,0 +1,126 @@
+// Package querylog logs the statements run through a dbtx.DB with log/slog:
+//
+//	db := dbtx.Wrap(sqlDB, querylog.New(logger, querylog.Options{
+//		SlowThreshold: 200 * time.Millisecond,
+//		Redact:        []string{"authors.bio"},
+//	}))
+//
+// Every statement is logged with its sqlc query name, or its SQL if it has
+// none, the method, the duration, the arguments, the rows affected by Exec,
+// the error and the request ID of the context (see package requestid).
+package querylog
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/requestid"
+)
+
+// Redacted replaces the values of redacted arguments.
+const Redacted = "[REDACTED]"
+
+// maxSQLLength bounds the SQL logged for statements without a name.
+const maxSQLLength = 200
+
+// Options configures the interceptor returned by New.
+type Options struct {
+	// Level is the level statements are logged at. Slow and failed
+	// statements are logged at slog.LevelWarn or above.
+	Level slog.Level
+	// SlowThreshold is the duration from which a statement is slow; zero
+	// means never.
+	SlowThreshold time.Duration
+	// Redact lists the "table.column"s whose argument values are replaced
+	// by Redacted, e.g. "authors.bio". When it is not empty, arguments that
+	// cannot be attributed to a column are replaced as well.
+	Redact []string
+}
+
+// New returns an interceptor that logs every statement to logger.
+func New(logger *slog.Logger, opts Options) dbtx.Interceptor {
+	redactor := newRedactor(opts.Redact)
+	return dbtx.Observe(func(ctx context.Context, q dbtx.Query, r dbtx.Result, d time.Duration) {
+		level := opts.Level
+		slow := opts.SlowThreshold > 0 && d >= opts.SlowThreshold
+		if (slow || r.Err != nil) && level < slog.LevelWarn {
+			level = slog.LevelWarn
+		}
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		attrs := make([]slog.Attr, 0, 8)
+		if q.Name != "" {
+			attrs = append(attrs, slog.String("query", q.Name))
+		} else {
+			attrs = append(attrs, slog.String("sql", compact(q.SQL)))
+		}
+		attrs = append(attrs,
+			slog.String("method", string(q.Method)),
+			slog.Duration("duration", d),
+		)
+		if slow {
+			attrs = append(attrs, slog.Bool("slow", true))
+		}
+		if len(q.Args) > 0 {
+			attrs = append(attrs, slog.Any("args", args(q.Args, redactor.redacted(q.Name, q.SQL, len(q.Args)))))
+		}
+		if r.Exec != nil && r.Err == nil {
+			if n, err := r.Exec.RowsAffected(); err == nil {
+				attrs = append(attrs, slog.Int64("rows_affected", n))
+			}
+		}
+		if r.Err != nil {
+			attrs = append(attrs, slog.String("error", r.Err.Error()))
+		}
+		if id := requestid.From(ctx); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		logger.LogAttrs(ctx, level, "query", attrs...)
+	})
+}
+
+// args returns the loggable values of args, with the ones marked in
+// redacted replaced.
+func args(args []any, redacted []bool) []any {
+	values := make([]any, len(args))
+	for i, arg := range args {
+		if i < len(redacted) && redacted[i] {
+			values[i] = Redacted
+			continue
+		}
+		values[i] = value(arg)
+	}
+	return values
+}
+
+// value returns arg as it reads best in a log, e.g. a binuuid.UUID in its
+// text form rather than its 16 bytes.
+func value(arg any) any {
+	switch v := arg.(type) {
+	case fmt.Stringer:
+		return v.String()
+	case driver.Valuer:
+		value, err := v.Value()
+		if err != nil {
+			return err.Error()
+		}
+		return value
+	}
+	return arg
+}
+
+// compact collapses the whitespace of query and truncates it.
+func compact(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > maxSQLLength {
+		query = query[:maxSQLLength] + "..."
+	}
+	return query
+}
@@ -0,0 +1,303 @@
+package querylog
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/requestid"
+)
+
+func TestRedacted(t *testing.T) {
+	tests := []struct {
+		scenario string
+		input    struct {
+			name  string
+			query string
+			args  int
+		}
+		expected []bool
+	}{
+		{
+			scenario: "insert",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				name:  "CreateAuthor",
+				query: "-- name: CreateAuthor :exec\nINSERT INTO\n  authors (uuid, name, bio)\nVALUES\n  (?, ?, ?)\n",
+				args:  3,
+			},
+			expected: []bool{false, false, true},
+		},
+		{
+			scenario: "multi-row insert",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "INSERT INTO authors (uuid, name, bio) VALUES (?, ?, ?), (?, ?, ?)",
+				args:  6,
+			},
+			expected: []bool{false, false, true, false, false, true},
+		},
+		{
+			scenario: "update",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				name:  "UpdateAuthor",
+				query: "-- name: UpdateAuthor :execrows\nUPDATE authors\nSET\n  name = ?,\n  bio = ?,\n  version = version + 1\nWHERE\n  uuid = ?\n  AND version = ?\n",
+				args:  4,
+			},
+			expected: []bool{false, true, false, false},
+		},
+		{
+			scenario: "aliased comparison and in list",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "SELECT a.uuid FROM books AS b INNER JOIN authors a ON a.uuid = b.uuid WHERE b.title = ? AND a.bio IN (?, ?)",
+				args:  3,
+			},
+			expected: []bool{false, true, true},
+		},
+		{
+			scenario: "other table",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "UPDATE books SET bio = ? WHERE uuid = ?",
+				args:  2,
+			},
+			expected: nil,
+		},
+		{
+			scenario: "string literal",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "SELECT uuid FROM authors WHERE name = 'bio = ?' AND uuid = ?",
+				args:  1,
+			},
+			expected: nil,
+		},
+		{
+			scenario: "limit and offset",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "SELECT uuid FROM authors WHERE uuid > ? ORDER BY uuid LIMIT ? OFFSET ?",
+				args:  3,
+			},
+			expected: nil,
+		},
+		{
+			scenario: "unattributed placeholders",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "SELECT uuid FROM authors WHERE MATCH (name, bio) AGAINST (? IN BOOLEAN MODE) AND deleted_at < CAST(? AS DATETIME(6)) LIMIT ?",
+				args:  3,
+			},
+			expected: []bool{true, true, false},
+		},
+		{
+			scenario: "more arguments than placeholders",
+			input: struct {
+				name  string
+				query string
+				args  int
+			}{
+				query: "SELECT uuid FROM authors WHERE uuid = ?",
+				args:  2,
+			},
+			expected: []bool{false, true},
+		},
+	}
+
+	r := newRedactor([]string{"Authors.Bio"})
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			got := r.redacted(tt.input.name, tt.input.query, tt.input.args)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got=%v, want=%v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// uuid is an argument that logs as text.
+type uuid [2]byte
+
+func (u uuid) String() string {
+	return "0001"
+}
+
+func TestNew(t *testing.T) {
+	errFailed := errors.New("failed")
+	ctx := requestid.With(context.Background(), "request001")
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			level  slog.Level
+			query  dbtx.Query
+			result dbtx.Result
+			delay  time.Duration
+		}
+		expected map[string]any
+	}{
+		{
+			scenario: "exec",
+			input: struct {
+				level  slog.Level
+				query  dbtx.Query
+				result dbtx.Result
+				delay  time.Duration
+			}{
+				query: dbtx.Query{
+					Method: dbtx.MethodExec,
+					Name:   "CreateAuthor",
+					SQL:    "-- name: CreateAuthor :exec\nINSERT INTO authors (uuid, name, bio) VALUES (?, ?, ?)",
+					Args:   []any{uuid{}, "author001", "bio of author001"},
+				},
+				result: dbtx.Result{Exec: driver.RowsAffected(1)},
+			},
+			expected: map[string]any{
+				"level":         "INFO",
+				"msg":           "query",
+				"query":         "CreateAuthor",
+				"method":        "Exec",
+				"args":          []any{"0001", "author001", Redacted},
+				"rows_affected": float64(1),
+				"request_id":    "request001",
+			},
+		},
+		{
+			scenario: "failed",
+			input: struct {
+				level  slog.Level
+				query  dbtx.Query
+				result dbtx.Result
+				delay  time.Duration
+			}{
+				level: slog.LevelDebug,
+				query: dbtx.Query{
+					Method: dbtx.MethodQuery,
+					Name:   "ListAuthors",
+					SQL:    "-- name: ListAuthors :many\nSELECT * FROM authors",
+				},
+				result: dbtx.Result{Err: errFailed},
+			},
+			expected: map[string]any{
+				"level":      "WARN",
+				"msg":        "query",
+				"query":      "ListAuthors",
+				"method":     "Query",
+				"error":      "failed",
+				"request_id": "request001",
+			},
+		},
+		{
+			scenario: "slow statement without name",
+			input: struct {
+				level  slog.Level
+				query  dbtx.Query
+				result dbtx.Result
+				delay  time.Duration
+			}{
+				query: dbtx.Query{
+					Method: dbtx.MethodExec,
+					SQL:    "SAVEPOINT\n  catalog_savepoint_1",
+				},
+				result: dbtx.Result{Exec: driver.ResultNoRows},
+				delay:  20 * time.Millisecond,
+			},
+			expected: map[string]any{
+				"level":      "WARN",
+				"msg":        "query",
+				"sql":        "SAVEPOINT catalog_savepoint_1",
+				"method":     "Exec",
+				"slow":       true,
+				"request_id": "request001",
+			},
+		},
+		{
+			scenario: "below handler level",
+			input: struct {
+				level  slog.Level
+				query  dbtx.Query
+				result dbtx.Result
+				delay  time.Duration
+			}{
+				level: slog.LevelDebug,
+				query: dbtx.Query{
+					Method: dbtx.MethodQueryRow,
+					Name:   "GetAuthor",
+					SQL:    "-- name: GetAuthor :one\nSELECT * FROM authors WHERE uuid = ?",
+				},
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			handler := dbtx.Chain(func(ctx context.Context, q dbtx.Query) dbtx.Result {
+				time.Sleep(tt.input.delay)
+				return tt.input.result
+			}, New(logger, Options{
+				Level:         tt.input.level,
+				SlowThreshold: 10 * time.Millisecond,
+				Redact:        []string{"authors.bio"},
+			}))
+			handler(ctx, tt.input.query)
+
+			var got map[string]any
+			if buf.Len() > 0 {
+				if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+					t.Fatal(err)
+				}
+				// the duration and time vary
+				if _, ok := got["duration"]; !ok {
+					t.Errorf("got=%v, want a duration", got)
+				}
+				delete(got, "duration")
+				delete(got, "time")
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("got=%v, want=%v", got, tt.expected)
+			}
+		})
+	}
+}
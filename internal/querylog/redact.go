@@ -0,0 +1,291 @@
+package querylog
+
+import (
+	"strings"
+	"sync"
+)
+
+// redactor finds the arguments of a statement that are bound to redacted
+// columns. It understands the statements sqlc and package catalog write:
+// the column list of INSERT ... VALUES, including multi-row inserts, and
+// comparisons and assignments such as "bio = ?" or "a.bio IN (?, ?)".
+// It fails closed: once a column is redacted, so is every argument it
+// cannot attribute to a column, e.g. one inside a function call.
+type redactor struct {
+	// columns holds the redacted "table.column"s in lower case.
+	columns map[string]bool
+	// named caches the result for the SQL of named queries. Other
+	// statements, e.g. multi-row inserts, vary too much to be worth it.
+	named sync.Map
+}
+
+func newRedactor(columns []string) *redactor {
+	r := &redactor{columns: make(map[string]bool, len(columns))}
+	for _, column := range columns {
+		r.columns[strings.ToLower(column)] = true
+	}
+	return r
+}
+
+// redacted reports for each of the n arguments of query whether it must
+// not be logged. It is nil if none must be hidden.
+func (r *redactor) redacted(name, query string, n int) []bool {
+	if len(r.columns) == 0 || n == 0 {
+		return nil
+	}
+	if name != "" {
+		if v, ok := r.named.Load(query); ok {
+			if redacted := v.([]bool); redacted == nil || len(redacted) == n {
+				return redacted
+			}
+		}
+	}
+
+	columns := placeholderColumns(query)
+	redacted := make([]bool, n)
+	found := false
+	for i := range redacted {
+		// Arguments the tokenizer did not see a placeholder for are
+		// unattributed as well.
+		redacted[i] = i >= len(columns) || columns[i] == "" || r.columns[columns[i]]
+		found = found || redacted[i]
+	}
+	if !found {
+		redacted = nil
+	}
+
+	if name != "" {
+		r.named.Store(query, redacted)
+	}
+	return redacted
+}
+
+// notColumn stands for a placeholder that is known not to be bound to a
+// column, such as the row count of LIMIT.
+const notColumn = "-"
+
+// placeholderColumns returns the "table.column", in lower case, that every
+// placeholder of query is bound to, notColumn for LIMIT and OFFSET, or ""
+// where it cannot tell.
+func placeholderColumns(query string) []string {
+	tokens := tokenize(query)
+	tables, main := tablesOf(tokens)
+	qualify := func(qualifier, column string) string {
+		table := main
+		if qualifier != "" {
+			table = qualifier
+			if t, ok := tables[qualifier]; ok {
+				table = t
+			}
+		}
+		return table + "." + column
+	}
+
+	var columns []string
+	insertColumns := insertColumnsOf(tokens)
+	// item is the index of the current item of a VALUES row, or -1 outside
+	// of one.
+	item, depth, inValues, seenValues := -1, 0, false, false
+	for i, tok := range tokens {
+		switch {
+		case tok == "values" && insertColumns != nil && !seenValues:
+			inValues, seenValues = true, true
+		case tok == "(":
+			depth++
+			if inValues && depth == 1 {
+				item = 0
+			}
+		case tok == ")":
+			depth--
+			if inValues && depth == 0 {
+				item = -1
+			}
+		case tok == "," && item >= 0 && depth == 1:
+			item++
+		case tok == "?":
+			switch {
+			case item >= 0 && item < len(insertColumns):
+				columns = append(columns, qualify("", insertColumns[item]))
+			case item >= 0:
+				columns = append(columns, "")
+			case i > 0 && (tokens[i-1] == "limit" || tokens[i-1] == "offset"),
+				i > 1 && tokens[i-2] == "limit" && tokens[i-1] == ",":
+				columns = append(columns, notColumn)
+			default:
+				qualifier, column := comparedColumn(tokens[:i])
+				if column == "" {
+					columns = append(columns, "")
+				} else {
+					columns = append(columns, qualify(qualifier, column))
+				}
+			}
+		case tok == "on" || tok == "where" || tok == "select":
+			inValues = false
+		}
+	}
+	return columns
+}
+
+// comparedColumn returns the column that the placeholder following tokens
+// is compared with or assigned to, and its qualifier if it has one.
+func comparedColumn(tokens []string) (qualifier, column string) {
+	i := len(tokens) - 1
+	if i < 0 {
+		return "", ""
+	}
+	switch tokens[i] {
+	case "=", "<", ">", "<=", ">=", "<>", "!=", "like":
+		i--
+	case "(", ",":
+		// An IN list: skip back to its opening parenthesis.
+		for i >= 0 && tokens[i] != "(" {
+			if tokens[i] != "," && tokens[i] != "?" {
+				return "", ""
+			}
+			i--
+		}
+		if i < 1 || tokens[i-1] != "in" {
+			return "", ""
+		}
+		i -= 2
+	default:
+		return "", ""
+	}
+	if i < 0 || !isIdentifier(tokens[i]) {
+		return "", ""
+	}
+	column = tokens[i]
+	if i >= 2 && tokens[i-1] == "." && isIdentifier(tokens[i-2]) {
+		qualifier = tokens[i-2]
+	}
+	return qualifier, column
+}
+
+// tablesOf returns the tables of the statement by alias, and the first of
+// them, which unqualified columns are taken to belong to.
+func tablesOf(tokens []string) (map[string]string, string) {
+	tables := make(map[string]string)
+	var main string
+	for i := 0; i+1 < len(tokens); i++ {
+		switch tokens[i] {
+		case "into", "update", "from", "join":
+		default:
+			continue
+		}
+		table := tokens[i+1]
+		if !isIdentifier(table) {
+			continue
+		}
+		if main == "" {
+			main = table
+		}
+		tables[table] = table
+		alias := i + 2
+		if alias < len(tokens) && tokens[alias] == "as" {
+			alias++
+		}
+		if alias < len(tokens) && isIdentifier(tokens[alias]) && !keywords[tokens[alias]] {
+			tables[tokens[alias]] = table
+		}
+	}
+	return tables, main
+}
+
+// insertColumnsOf returns the column list of an INSERT statement.
+func insertColumnsOf(tokens []string) []string {
+	if len(tokens) < 4 || tokens[0] != "insert" || tokens[1] != "into" || tokens[3] != "(" {
+		return nil
+	}
+	var columns []string
+	for _, tok := range tokens[4:] {
+		if tok == ")" {
+			return columns
+		}
+		if tok != "," {
+			columns = append(columns, tok)
+		}
+	}
+	return nil
+}
+
+// keywords may follow a table name and are not an alias.
+var keywords = map[string]bool{
+	"as": true, "where": true, "set": true, "on": true, "inner": true, "left": true,
+	"right": true, "join": true, "order": true, "group": true, "limit": true, "values": true,
+}
+
+func isIdentifier(tok string) bool {
+	if tok == "" {
+		return false
+	}
+	c := tok[0]
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 0x80
+}
+
+// tokenize splits query into lower-case identifiers and keywords, "?",
+// numbers, comparison operators and single punctuation characters.
+// Comments are dropped, string literals become "'" and backquotes are
+// removed.
+func tokenize(query string) []string {
+	var tokens []string
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case strings.HasPrefix(query[i:], "--") || c == '#':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			i += end + 4
+		case c == '\'' || c == '"':
+			i++
+			for i < len(query) && query[i] != c {
+				if query[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+			// Keep the literal's place, so that it is not taken for a
+			// column.
+			tokens = append(tokens, "'")
+		case c == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				return tokens
+			}
+			tokens = append(tokens, strings.ToLower(query[i+1:i+1+end]))
+			i += end + 2
+		case c == '<' || c == '>' || c == '!':
+			if i+1 < len(query) && (query[i+1] == '=' || c == '<' && query[i+1] == '>') {
+				tokens = append(tokens, query[i:i+2])
+				i += 2
+			} else {
+				tokens = append(tokens, query[i:i+1])
+				i++
+			}
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80:
+			start := i
+			for i < len(query) {
+				c := query[i]
+				if c != '_' && c != '$' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c < 0x80 {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, strings.ToLower(query[start:i]))
+		default:
+			tokens = append(tokens, query[i:i+1])
+			i++
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,652 @@
+// Package querytest is a conformance suite for implementations of
+// sqlc.Querier. It runs against *sqlc.Queries on MySQL and against the
+// in-memory memdb.DB, so that the fake keeps behaving like the schema in
+// db/migrations: the same rows, in the same order, and the same domain
+// errors after dberr.Translate.
+package querytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+var (
+	minTime = time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	maxTime = time.Date(9999, time.December, 31, 23, 59, 59, 999999000, time.UTC)
+)
+
+// Run runs every check as a parallel subtest of t. newQuerier returns an
+// empty database for a single subtest.
+func Run(t *testing.T, newQuerier func(t *testing.T) sqlc.Querier) {
+	tests := []struct {
+		scenario string
+		check    func(t *testing.T, q sqlc.Querier)
+	}{
+		{scenario: "create and get author", check: checkCreateAuthor},
+		{scenario: "update author with version", check: checkUpdateAuthor},
+		{scenario: "delete and restore author", check: checkDeleteAuthor},
+		{scenario: "list authors", check: checkListAuthors},
+		{scenario: "purge authors", check: checkPurgeAuthors},
+		{scenario: "create and get publisher", check: checkCreatePublisher},
+		{scenario: "list publishers by name", check: checkListPublishersByName},
+		{scenario: "purge publishers", check: checkPurgePublishers},
+		{scenario: "create book", check: checkCreateBook},
+		{scenario: "get book publisher", check: checkGetBookPublisher},
+		{scenario: "list books", check: checkListBooks},
+		{scenario: "reassign books", check: checkReassignBooks},
+		{scenario: "purge books", check: checkPurgeBooks},
+		{scenario: "create author book", check: checkCreateAuthorBook},
+		{scenario: "list author books", check: checkListAuthorBooks},
+		{scenario: "list books by author and authors by book", check: checkListByLink},
+		{scenario: "list author names of books", check: checkListAuthorNamesOfBooks},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+			tt.check(t, newQuerier(t))
+		})
+	}
+}
+
+// uuid returns a UUID that sorts as n.
+func uuid(n int) binuuid.UUID {
+	return binuuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", n))
+}
+
+func ok(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// isErr checks that err translates to want.
+func isErr(t *testing.T, err error, want error) {
+	t.Helper()
+	if got := dberr.Translate(err); !errors.Is(got, want) {
+		t.Errorf("got=%v, want=%v", got, want)
+	}
+}
+
+func affected(t *testing.T, n int64, err error, want int64) {
+	t.Helper()
+	ok(t, err)
+	if n != want {
+		t.Errorf("got=%v rows, want=%v rows", n, want)
+	}
+}
+
+// equal compares got and want after checking and clearing the timestamps
+// set by the database, which differ between implementations.
+func equal[T any](t *testing.T, got T, want T) {
+	t.Helper()
+	clearTimestamps(t, reflect.ValueOf(&got).Elem())
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got=%+v, want=%+v", got, want)
+	}
+}
+
+func clearTimestamps(t *testing.T, v reflect.Value) {
+	t.Helper()
+	switch v.Kind() {
+	case reflect.Slice:
+		for i := range v.Len() {
+			clearTimestamps(t, v.Index(i))
+		}
+	case reflect.Struct:
+		for _, name := range []string{"CreatedAt", "UpdatedAt"} {
+			if f := v.FieldByName(name); f.IsValid() {
+				if f.Interface().(time.Time).IsZero() {
+					t.Errorf("%s: %s is zero", v.Type().Name(), name)
+				}
+				f.Set(reflect.Zero(f.Type()))
+			}
+		}
+		if f := v.FieldByName("DeletedAt"); f.IsValid() {
+			deletedAt := f.Interface().(sql.NullTime)
+			if deletedAt.Valid && deletedAt.Time.IsZero() {
+				t.Errorf("%s: DeletedAt is zero", v.Type().Name())
+			}
+			f.Set(reflect.ValueOf(sql.NullTime{Valid: deletedAt.Valid}))
+		}
+	}
+}
+
+func uuidsOf[T any](rows []T, uuid func(T) binuuid.UUID) []binuuid.UUID {
+	uuids := []binuuid.UUID{}
+	for _, row := range rows {
+		uuids = append(uuids, uuid(row))
+	}
+	return uuids
+}
+
+func authorUuid(a sqlc.Author) binuuid.UUID       { return a.Uuid }
+func publisherUuid(p sqlc.Publisher) binuuid.UUID { return p.Uuid }
+func bookUuid(b sqlc.Book) binuuid.UUID           { return b.Uuid }
+
+// fixture creates publisher 1 with books 11 and 12, and authors 21 and 22
+// who both wrote book 11.
+func fixture(t *testing.T, q sqlc.Querier) {
+	t.Helper()
+	ctx := context.Background()
+
+	ok(t, q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: uuid(1), Name: "publisher001"}))
+	for _, n := range []int{11, 12} {
+		ok(t, q.CreateBook(ctx, sqlc.CreateBookParams{Uuid: uuid(n), Title: fmt.Sprintf("book%03d", n), PublisherUuid: uuid(1)}))
+	}
+	for _, n := range []int{21, 22} {
+		ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(n), Name: fmt.Sprintf("author%03d", n)}))
+		ok(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(n), BookUuid: uuid(11)}))
+	}
+}
+
+func checkCreateAuthor(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	params := sqlc.CreateAuthorParams{Uuid: uuid(1), Name: "author001", Bio: sql.NullString{String: "bio001", Valid: true}}
+	ok(t, q.CreateAuthor(ctx, params))
+
+	want := sqlc.Author{Uuid: uuid(1), Name: "author001", Bio: params.Bio, Version: 1}
+	got, err := q.GetAuthor(ctx, uuid(1))
+	ok(t, err)
+	equal(t, got, want)
+	got, err = q.GetAuthorIncludingDeleted(ctx, uuid(1))
+	ok(t, err)
+	equal(t, got, want)
+
+	isErr(t, q.CreateAuthor(ctx, params), dberr.ErrAlreadyExists)
+
+	_, err = q.GetAuthor(ctx, uuid(2))
+	isErr(t, err, dberr.ErrNotFound)
+	_, err = q.GetAuthorIncludingDeleted(ctx, uuid(2))
+	isErr(t, err, dberr.ErrNotFound)
+}
+
+func checkUpdateAuthor(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(1), Name: "author001"}))
+
+	params := sqlc.UpdateAuthorParams{Uuid: uuid(1), Name: "author002", Version: 1}
+	n, err := q.UpdateAuthor(ctx, params)
+	affected(t, n, err, 1)
+	// stale version
+	n, err = q.UpdateAuthor(ctx, params)
+	affected(t, n, err, 0)
+	// missing author
+	n, err = q.UpdateAuthor(ctx, sqlc.UpdateAuthorParams{Uuid: uuid(2), Name: "author002", Version: 1})
+	affected(t, n, err, 0)
+
+	got, err := q.GetAuthor(ctx, uuid(1))
+	ok(t, err)
+	equal(t, got, sqlc.Author{Uuid: uuid(1), Name: "author002", Version: 2})
+
+	// deleted authors cannot be updated
+	n, err = q.DeleteAuthor(ctx, uuid(1))
+	affected(t, n, err, 1)
+	n, err = q.UpdateAuthor(ctx, sqlc.UpdateAuthorParams{Uuid: uuid(1), Name: "author003", Version: 2})
+	affected(t, n, err, 0)
+}
+
+func checkDeleteAuthor(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(1), Name: "author001"}))
+
+	n, err := q.DeleteAuthor(ctx, uuid(1))
+	affected(t, n, err, 1)
+	n, err = q.DeleteAuthor(ctx, uuid(1))
+	affected(t, n, err, 0)
+
+	_, err = q.GetAuthor(ctx, uuid(1))
+	isErr(t, err, dberr.ErrNotFound)
+	got, err := q.GetAuthorIncludingDeleted(ctx, uuid(1))
+	ok(t, err)
+	equal(t, got, sqlc.Author{Uuid: uuid(1), Name: "author001", DeletedAt: sql.NullTime{Valid: true}, Version: 2})
+	authors, err := q.ListAuthors(ctx)
+	ok(t, err)
+	equal(t, len(authors), 0)
+
+	n, err = q.RestoreAuthor(ctx, uuid(1))
+	affected(t, n, err, 1)
+	n, err = q.RestoreAuthor(ctx, uuid(1))
+	affected(t, n, err, 0)
+
+	got, err = q.GetAuthor(ctx, uuid(1))
+	ok(t, err)
+	equal(t, got, sqlc.Author{Uuid: uuid(1), Name: "author001", Version: 3})
+}
+
+func checkListAuthors(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	for _, n := range []int{3, 1, 4, 2} {
+		ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(n), Name: fmt.Sprintf("author%03d", n)}))
+	}
+	n, err := q.DeleteAuthor(ctx, uuid(4))
+	affected(t, n, err, 1)
+
+	authors, err := q.ListAuthors(ctx)
+	ok(t, err)
+	equal(t, uuidsOf(authors, authorUuid), []binuuid.UUID{uuid(1), uuid(2), uuid(3)})
+
+	authors, err = q.ListAuthorsPage(ctx, sqlc.ListAuthorsPageParams{
+		AfterUuid:   uuid(1),
+		CreatedFrom: minTime,
+		CreatedTo:   maxTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       1,
+	})
+	ok(t, err)
+	equal(t, uuidsOf(authors, authorUuid), []binuuid.UUID{uuid(2)})
+
+	authors, err = q.ListAuthorsPageIncludingDeleted(ctx, sqlc.ListAuthorsPageIncludingDeletedParams{
+		AfterUuid:   uuid(2),
+		CreatedFrom: minTime,
+		CreatedTo:   maxTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       10,
+	})
+	ok(t, err)
+	equal(t, uuidsOf(authors, authorUuid), []binuuid.UUID{uuid(3), uuid(4)})
+
+	// created_to is exclusive
+	authors, err = q.ListAuthorsPage(ctx, sqlc.ListAuthorsPageParams{
+		CreatedFrom: minTime,
+		CreatedTo:   minTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       10,
+	})
+	ok(t, err)
+	equal(t, len(authors), 0)
+}
+
+func checkPurgeAuthors(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(23), Name: "author023"}))
+	for _, author := range []int{21, 23} {
+		n, err := q.DeleteAuthor(ctx, uuid(author))
+		affected(t, n, err, 1)
+	}
+	future := time.Now().Add(time.Hour)
+
+	// nothing was deleted before the past
+	n, err := q.PurgeAuthors(ctx, time.Now().Add(-time.Hour))
+	affected(t, n, err, 0)
+
+	// author 21 still has a link
+	_, err = q.PurgeAuthors(ctx, future)
+	isErr(t, err, dberr.ErrInUse)
+	_, err = q.GetAuthorIncludingDeleted(ctx, uuid(23))
+	ok(t, err)
+
+	n, err = q.PurgeAuthorBooksOfAuthors(ctx, future)
+	affected(t, n, err, 1)
+	n, err = q.PurgeAuthors(ctx, future)
+	affected(t, n, err, 2)
+	_, err = q.GetAuthorIncludingDeleted(ctx, uuid(21))
+	isErr(t, err, dberr.ErrNotFound)
+	_, err = q.GetAuthor(ctx, uuid(22))
+	ok(t, err)
+}
+
+func checkCreatePublisher(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	params := sqlc.CreatePublisherParams{Uuid: uuid(1), Name: "publisher001"}
+	ok(t, q.CreatePublisher(ctx, params))
+	isErr(t, q.CreatePublisher(ctx, params), dberr.ErrAlreadyExists)
+
+	n, err := q.UpdatePublisher(ctx, sqlc.UpdatePublisherParams{Uuid: uuid(1), Name: "publisher002", Version: 1})
+	affected(t, n, err, 1)
+	got, err := q.GetPublisher(ctx, uuid(1))
+	ok(t, err)
+	equal(t, got, sqlc.Publisher{Uuid: uuid(1), Name: "publisher002", Version: 2})
+
+	n, err = q.DeletePublisher(ctx, uuid(1))
+	affected(t, n, err, 1)
+	_, err = q.GetPublisher(ctx, uuid(1))
+	isErr(t, err, dberr.ErrNotFound)
+	publishers, err := q.ListPublishersPageIncludingDeleted(ctx, sqlc.ListPublishersPageIncludingDeletedParams{
+		CreatedFrom: minTime,
+		CreatedTo:   maxTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       10,
+	})
+	ok(t, err)
+	equal(t, publishers, []sqlc.Publisher{{Uuid: uuid(1), Name: "publisher002", DeletedAt: sql.NullTime{Valid: true}, Version: 3}})
+
+	n, err = q.RestorePublisher(ctx, uuid(1))
+	affected(t, n, err, 1)
+	publishers, err = q.ListPublishers(ctx)
+	ok(t, err)
+	equal(t, uuidsOf(publishers, publisherUuid), []binuuid.UUID{uuid(1)})
+}
+
+func checkListPublishersByName(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	for n, name := range []string{"acme", "ACME", "acme", "other", "acme"} {
+		ok(t, q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: uuid(n + 1), Name: name}))
+	}
+	n, err := q.DeletePublisher(ctx, uuid(1))
+	affected(t, n, err, 1)
+
+	// case-insensitive, without deleted publishers, at most two
+	publishers, err := q.ListPublishersByName(ctx, "Acme")
+	ok(t, err)
+	equal(t, uuidsOf(publishers, publisherUuid), []binuuid.UUID{uuid(2), uuid(3)})
+
+	publishers, err = q.ListPublishersPage(ctx, sqlc.ListPublishersPageParams{
+		AfterUuid:   uuid(3),
+		CreatedFrom: minTime,
+		CreatedTo:   maxTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       10,
+	})
+	ok(t, err)
+	equal(t, uuidsOf(publishers, publisherUuid), []binuuid.UUID{uuid(4), uuid(5)})
+
+	publishers, err = q.ListPublishersByName(ctx, "missing")
+	ok(t, err)
+	equal(t, len(publishers), 0)
+}
+
+func checkPurgePublishers(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	ok(t, q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: uuid(2), Name: "publisher002"}))
+	for _, publisher := range []int{1, 2} {
+		n, err := q.DeletePublisher(ctx, uuid(publisher))
+		affected(t, n, err, 1)
+	}
+
+	// publisher 1 still has books and is kept
+	n, err := q.PurgePublishers(ctx, time.Now().Add(time.Hour))
+	affected(t, n, err, 1)
+	_, err = q.GetPublisherIncludingDeleted(ctx, uuid(1))
+	ok(t, err)
+	_, err = q.GetPublisherIncludingDeleted(ctx, uuid(2))
+	isErr(t, err, dberr.ErrNotFound)
+}
+
+func checkCreateBook(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	params := sqlc.CreateBookParams{Uuid: uuid(11), Title: "book011", PublisherUuid: uuid(1)}
+	isErr(t, q.CreateBook(ctx, params), dberr.ErrReferenceMissing)
+
+	// a deleted publisher still satisfies the foreign key
+	ok(t, q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: uuid(1), Name: "publisher001"}))
+	n, err := q.DeletePublisher(ctx, uuid(1))
+	affected(t, n, err, 1)
+	ok(t, q.CreateBook(ctx, params))
+	isErr(t, q.CreateBook(ctx, params), dberr.ErrAlreadyExists)
+
+	got, err := q.GetBook(ctx, uuid(11))
+	ok(t, err)
+	equal(t, got, sqlc.Book{Uuid: uuid(11), Title: "book011", PublisherUuid: uuid(1), Version: 1})
+
+	count, err := q.CountBooksByPublisher(ctx, uuid(1))
+	affected(t, count, err, 1)
+
+	n, err = q.UpdateBook(ctx, sqlc.UpdateBookParams{Uuid: uuid(11), Title: "book012", Version: 1})
+	affected(t, n, err, 1)
+	n, err = q.DeleteBook(ctx, uuid(11))
+	affected(t, n, err, 1)
+	count, err = q.CountBooksByPublisher(ctx, uuid(1))
+	affected(t, count, err, 0)
+	got, err = q.GetBookIncludingDeleted(ctx, uuid(11))
+	ok(t, err)
+	equal(t, got, sqlc.Book{Uuid: uuid(11), Title: "book012", PublisherUuid: uuid(1), DeletedAt: sql.NullTime{Valid: true}, Version: 3})
+
+	n, err = q.RestoreBook(ctx, uuid(11))
+	affected(t, n, err, 1)
+	_, err = q.GetBook(ctx, uuid(11))
+	ok(t, err)
+}
+
+func checkGetBookPublisher(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+
+	got, err := q.GetBookPublisher(ctx, uuid(11))
+	ok(t, err)
+	equal(t, got, sqlc.GetBookPublisherRow{BookUuid: uuid(11), BookTitle: "book011", PublisherUuid: uuid(1), PublisherName: "publisher001"})
+
+	n, err := q.DeleteBook(ctx, uuid(12))
+	affected(t, n, err, 1)
+	rows, err := q.GetPublisherBooks(ctx, uuid(1))
+	ok(t, err)
+	equal(t, rows, []sqlc.GetPublisherBooksRow{{PublisherUuid: uuid(1), PublisherName: "publisher001", BookUuid: uuid(11), BookTitle: "book011"}})
+
+	n, err = q.DeletePublisher(ctx, uuid(1))
+	affected(t, n, err, 1)
+	_, err = q.GetBookPublisher(ctx, uuid(11))
+	isErr(t, err, dberr.ErrNotFound)
+	rows, err = q.GetPublisherBooks(ctx, uuid(1))
+	ok(t, err)
+	equal(t, len(rows), 0)
+}
+
+func checkListBooks(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	ok(t, q.CreateBook(ctx, sqlc.CreateBookParams{Uuid: uuid(13), Title: "book013", PublisherUuid: uuid(1)}))
+	n, err := q.DeleteBook(ctx, uuid(12))
+	affected(t, n, err, 1)
+
+	books, err := q.ListBooks(ctx)
+	ok(t, err)
+	equal(t, uuidsOf(books, bookUuid), []binuuid.UUID{uuid(11), uuid(13)})
+
+	books, err = q.ListBooksPageIncludingDeleted(ctx, sqlc.ListBooksPageIncludingDeletedParams{
+		AfterUuid:   uuid(11),
+		CreatedFrom: minTime,
+		CreatedTo:   maxTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       1,
+	})
+	ok(t, err)
+	equal(t, uuidsOf(books, bookUuid), []binuuid.UUID{uuid(12)})
+
+	books, err = q.ListBooksPage(ctx, sqlc.ListBooksPageParams{
+		AfterUuid:   uuid(11),
+		CreatedFrom: minTime,
+		CreatedTo:   maxTime,
+		UpdatedFrom: minTime,
+		UpdatedTo:   maxTime,
+		Limit:       1,
+	})
+	ok(t, err)
+	equal(t, uuidsOf(books, bookUuid), []binuuid.UUID{uuid(13)})
+
+	summaries, err := q.ListBookSummariesPage(ctx, sqlc.ListBookSummariesPageParams{AfterUuid: binuuid.Nil, Limit: 10})
+	ok(t, err)
+	equal(t, summaries, []sqlc.ListBookSummariesPageRow{
+		{Title: "book011", Uuid: uuid(11), PublisherUuid: uuid(1), Version: 1, PublisherName: "publisher001"},
+		{Title: "book013", Uuid: uuid(13), PublisherUuid: uuid(1), Version: 1, PublisherName: "publisher001"},
+	})
+}
+
+func checkReassignBooks(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	n, err := q.DeleteBook(ctx, uuid(12))
+	affected(t, n, err, 1)
+
+	_, err = q.ReassignBooks(ctx, sqlc.ReassignBooksParams{FromPublisherUuid: uuid(1), ToPublisherUuid: uuid(2)})
+	isErr(t, err, dberr.ErrReferenceMissing)
+	// without books to move the target is not checked
+	n, err = q.ReassignBooks(ctx, sqlc.ReassignBooksParams{FromPublisherUuid: uuid(3), ToPublisherUuid: uuid(2)})
+	affected(t, n, err, 0)
+
+	// deleted books move too
+	ok(t, q.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: uuid(2), Name: "publisher002"}))
+	n, err = q.ReassignBooks(ctx, sqlc.ReassignBooksParams{FromPublisherUuid: uuid(1), ToPublisherUuid: uuid(2)})
+	affected(t, n, err, 2)
+	book, err := q.GetBookIncludingDeleted(ctx, uuid(12))
+	ok(t, err)
+	equal(t, book.PublisherUuid, uuid(2))
+}
+
+func checkPurgeBooks(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	for _, book := range []int{11, 12} {
+		n, err := q.DeleteBook(ctx, uuid(book))
+		affected(t, n, err, 1)
+	}
+	future := time.Now().Add(time.Hour)
+
+	// book 11 still has links
+	_, err := q.PurgeBooks(ctx, future)
+	isErr(t, err, dberr.ErrInUse)
+	_, err = q.GetBookIncludingDeleted(ctx, uuid(12))
+	ok(t, err)
+
+	n, err := q.PurgeAuthorBooksOfBooks(ctx, future)
+	affected(t, n, err, 2)
+	n, err = q.PurgeBooks(ctx, future)
+	affected(t, n, err, 2)
+	_, err = q.GetBookIncludingDeleted(ctx, uuid(11))
+	isErr(t, err, dberr.ErrNotFound)
+}
+
+func checkCreateAuthorBook(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+
+	isErr(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(29), BookUuid: uuid(12)}), dberr.ErrReferenceMissing)
+	isErr(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(19)}), dberr.ErrReferenceMissing)
+	isErr(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)}), dberr.ErrAlreadyExists)
+
+	got, err := q.GetAuthorBook(ctx, sqlc.GetAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	ok(t, err)
+	equal(t, got, sqlc.AuthorBook{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+
+	n, err := q.DeleteAuthorBook(ctx, sqlc.DeleteAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	affected(t, n, err, 1)
+	n, err = q.DeleteAuthorBook(ctx, sqlc.DeleteAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	affected(t, n, err, 0)
+	_, err = q.GetAuthorBook(ctx, sqlc.GetAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	isErr(t, err, dberr.ErrNotFound)
+
+	// links to a deleted author or book are hidden until it is restored
+	ok(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)}))
+	n, err = q.DeleteAuthor(ctx, uuid(21))
+	affected(t, n, err, 1)
+	_, err = q.GetAuthorBook(ctx, sqlc.GetAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	isErr(t, err, dberr.ErrNotFound)
+	n, err = q.RestoreAuthor(ctx, uuid(21))
+	affected(t, n, err, 1)
+	n, err = q.DeleteBook(ctx, uuid(11))
+	affected(t, n, err, 1)
+	_, err = q.GetAuthorBook(ctx, sqlc.GetAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	isErr(t, err, dberr.ErrNotFound)
+	n, err = q.RestoreBook(ctx, uuid(11))
+	affected(t, n, err, 1)
+	_, err = q.GetAuthorBook(ctx, sqlc.GetAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(11)})
+	ok(t, err)
+
+	ok(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(22), BookUuid: uuid(12)}))
+	n, err = q.DeleteAuthorBooksByAuthor(ctx, uuid(22))
+	affected(t, n, err, 2)
+}
+
+// authorBooksPager is implemented by *sqlc.Queries and memdb.DB. The query
+// is written by hand, so sqlc.Querier does not include it.
+type authorBooksPager interface {
+	ListAuthorBooksPage(ctx context.Context, arg sqlc.ListAuthorBooksPageParams) ([]sqlc.ListAuthorBooksPageRow, error)
+}
+
+func checkListAuthorBooks(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	ok(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(12)}))
+
+	rows, err := q.ListAuthorBooks(ctx)
+	ok(t, err)
+	equal(t, rows, []sqlc.ListAuthorBooksRow{
+		{AuthorUuid: uuid(21), AuthorName: "author021", BookUuid: uuid(11), BookTitle: "book011"},
+		{AuthorUuid: uuid(21), AuthorName: "author021", BookUuid: uuid(12), BookTitle: "book012"},
+		{AuthorUuid: uuid(22), AuthorName: "author022", BookUuid: uuid(11), BookTitle: "book011"},
+	})
+
+	n, err := q.DeleteBook(ctx, uuid(11))
+	affected(t, n, err, 1)
+	pager, isPager := q.(authorBooksPager)
+	if !isPager {
+		t.Fatalf("got=%T, want a ListAuthorBooksPage method", q)
+	}
+	page, err := pager.ListAuthorBooksPage(ctx, sqlc.ListAuthorBooksPageParams{
+		AfterAuthorUuid: uuid(21),
+		AfterBookUuid:   uuid(11),
+		CreatedFrom:     minTime,
+		CreatedTo:       maxTime,
+		Limit:           10,
+	})
+	ok(t, err)
+	equal(t, page, []sqlc.ListAuthorBooksPageRow{
+		{AuthorUuid: uuid(21), AuthorName: "author021", BookUuid: uuid(12), BookTitle: "book012"},
+	})
+}
+
+func checkListByLink(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	ok(t, q.CreateBook(ctx, sqlc.CreateBookParams{Uuid: uuid(13), Title: "book013", PublisherUuid: uuid(1)}))
+	for _, n := range []int{12, 13} {
+		ok(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(21), BookUuid: uuid(n)}))
+	}
+	n, err := q.DeleteBook(ctx, uuid(12))
+	affected(t, n, err, 1)
+	n, err = q.DeleteAuthor(ctx, uuid(22))
+	affected(t, n, err, 1)
+
+	books, err := q.ListBooksByAuthor(ctx, sqlc.ListBooksByAuthorParams{AuthorUuid: uuid(21), AfterUuid: binuuid.Nil, Limit: 10})
+	ok(t, err)
+	equal(t, uuidsOf(books, bookUuid), []binuuid.UUID{uuid(11), uuid(13)})
+	books, err = q.ListBooksByAuthor(ctx, sqlc.ListBooksByAuthorParams{AuthorUuid: uuid(21), AfterUuid: uuid(11), Limit: 1})
+	ok(t, err)
+	equal(t, uuidsOf(books, bookUuid), []binuuid.UUID{uuid(13)})
+
+	authors, err := q.ListAuthorsByBook(ctx, sqlc.ListAuthorsByBookParams{BookUuid: uuid(11), AfterUuid: binuuid.Nil, Limit: 10})
+	ok(t, err)
+	equal(t, uuidsOf(authors, authorUuid), []binuuid.UUID{uuid(21)})
+}
+
+func checkListAuthorNamesOfBooks(t *testing.T, q sqlc.Querier) {
+	ctx := context.Background()
+	fixture(t, q)
+	ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(20), Name: "author099"}))
+	ok(t, q.CreateAuthor(ctx, sqlc.CreateAuthorParams{Uuid: uuid(23), Name: "author023"}))
+	for _, n := range []int{20, 23} {
+		ok(t, q.CreateAuthorBook(ctx, sqlc.CreateAuthorBookParams{AuthorUuid: uuid(n), BookUuid: uuid(12)}))
+	}
+	n, err := q.DeleteAuthor(ctx, uuid(22))
+	affected(t, n, err, 1)
+
+	rows, err := q.ListAuthorNamesOfBooks(ctx, []binuuid.UUID{uuid(12), uuid(11), uuid(19)})
+	ok(t, err)
+	equal(t, rows, []sqlc.ListAuthorNamesOfBooksRow{
+		{BookUuid: uuid(11), AuthorName: "author021"},
+		{BookUuid: uuid(12), AuthorName: "author023"},
+		{BookUuid: uuid(12), AuthorName: "author099"},
+	})
+
+	rows, err = q.ListAuthorNamesOfBooks(ctx, nil)
+	ok(t, err)
+	equal(t, len(rows), 0)
+}
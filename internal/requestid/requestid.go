@@ -0,0 +1,48 @@
+// Package requestid carries a request or correlation ID in a context, so
+// that everything logged on behalf of one request or command can be found
+// together.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header that carries the ID of a request.
+const Header = "X-Request-Id"
+
+// MaxLength bounds the IDs accepted from clients.
+const MaxLength = 128
+
+type contextKey struct{}
+
+// New returns a random ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// With returns ctx carrying id.
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// From returns the ID carried by ctx, or "" if there is none.
+func From(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// Valid reports whether id, e.g. received in Header, may be used as an ID:
+// it is not empty, at most MaxLength bytes long and printable ASCII.
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -8,8 +8,10 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 )
 
 const createAuthorBook = `-- name: CreateAuthorBook :exec
@@ -20,8 +22,8 @@ VALUES
 `
 
 type CreateAuthorBookParams struct {
-	AuthorUuid uuid.UUID
-	BookUuid   uuid.UUID
+	AuthorUuid binuuid.UUID
+	BookUuid   binuuid.UUID
 }
 
 func (q *Queries) CreateAuthorBook(ctx context.Context, arg CreateAuthorBookParams) error {
@@ -29,7 +31,7 @@ func (q *Queries) CreateAuthorBook(ctx context.Context, arg CreateAuthorBookPara
 	return err
 }
 
-const deleteAuthorBook = `-- name: DeleteAuthorBook :exec
+const deleteAuthorBook = `-- name: DeleteAuthorBook :execrows
 DELETE FROM author_books
 WHERE
   author_uuid = ?
@@ -37,36 +39,62 @@ WHERE
 `
 
 type DeleteAuthorBookParams struct {
-	AuthorUuid uuid.UUID
-	BookUuid   uuid.UUID
+	AuthorUuid binuuid.UUID
+	BookUuid   binuuid.UUID
 }
 
-func (q *Queries) DeleteAuthorBook(ctx context.Context, arg DeleteAuthorBookParams) error {
-	_, err := q.db.ExecContext(ctx, deleteAuthorBook, arg.AuthorUuid, arg.BookUuid)
-	return err
+func (q *Queries) DeleteAuthorBook(ctx context.Context, arg DeleteAuthorBookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuthorBook, arg.AuthorUuid, arg.BookUuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteAuthorBooksByAuthor = `-- name: DeleteAuthorBooksByAuthor :execrows
+DELETE FROM author_books
+WHERE
+  author_uuid = ?
+`
+
+func (q *Queries) DeleteAuthorBooksByAuthor(ctx context.Context, authorUuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuthorBooksByAuthor, authorUuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getAuthorBook = `-- name: GetAuthorBook :one
 SELECT
-  author_uuid, book_uuid
+  ab.author_uuid, ab.book_uuid, ab.created_at, ab.updated_at
 FROM
-  author_books
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+  INNER JOIN books AS b ON ab.book_uuid = b.uuid
 WHERE
-  author_uuid = ?
-  AND book_uuid = ?
+  ab.author_uuid = ?
+  AND ab.book_uuid = ?
+  AND a.deleted_at IS NULL
+  AND b.deleted_at IS NULL
 LIMIT
   1
 `
 
 type GetAuthorBookParams struct {
-	AuthorUuid uuid.UUID
-	BookUuid   uuid.UUID
+	AuthorUuid binuuid.UUID
+	BookUuid   binuuid.UUID
 }
 
 func (q *Queries) GetAuthorBook(ctx context.Context, arg GetAuthorBookParams) (AuthorBook, error) {
 	row := q.db.QueryRowContext(ctx, getAuthorBook, arg.AuthorUuid, arg.BookUuid)
 	var i AuthorBook
-	err := row.Scan(&i.AuthorUuid, &i.BookUuid)
+	err := row.Scan(
+		&i.AuthorUuid,
+		&i.BookUuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
 	return i, err
 }
 
@@ -81,16 +109,19 @@ FROM
   authors AS a
   INNER JOIN author_books AS ab ON a.uuid = ab.author_uuid
   INNER JOIN books AS b ON ab.book_uuid = b.uuid
+WHERE
+  a.deleted_at IS NULL
+  AND b.deleted_at IS NULL
 ORDER BY
   a.uuid,
   b.uuid
 `
 
 type ListAuthorBooksRow struct {
-	AuthorUuid uuid.UUID
+	AuthorUuid binuuid.UUID
 	AuthorName string
 	AuthorBio  sql.NullString
-	BookUuid   uuid.UUID
+	BookUuid   binuuid.UUID
 	BookTitle  string
 }
 
@@ -122,3 +153,205 @@ func (q *Queries) ListAuthorBooks(ctx context.Context) ([]ListAuthorBooksRow, er
 	}
 	return items, nil
 }
+
+const listAuthorNamesOfBooks = `-- name: ListAuthorNamesOfBooks :many
+SELECT
+  ab.book_uuid,
+  a.name AS author_name
+FROM
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+WHERE
+  ab.book_uuid IN (/*SLICE:book_uuids*/?)
+  AND a.deleted_at IS NULL
+ORDER BY
+  ab.book_uuid,
+  a.name,
+  a.uuid
+`
+
+type ListAuthorNamesOfBooksRow struct {
+	BookUuid   binuuid.UUID
+	AuthorName string
+}
+
+func (q *Queries) ListAuthorNamesOfBooks(ctx context.Context, bookUuids []binuuid.UUID) ([]ListAuthorNamesOfBooksRow, error) {
+	query := listAuthorNamesOfBooks
+	var queryParams []interface{}
+	if len(bookUuids) > 0 {
+		for _, v := range bookUuids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:book_uuids*/?", strings.Repeat(",?", len(bookUuids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:book_uuids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuthorNamesOfBooksRow
+	for rows.Next() {
+		var i ListAuthorNamesOfBooksRow
+		if err := rows.Scan(&i.BookUuid, &i.AuthorName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsByBook = `-- name: ListAuthorsByBook :many
+SELECT
+  a.name, a.bio, a.uuid, a.created_at, a.updated_at, a.deleted_at, a.version
+FROM
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+WHERE
+  ab.book_uuid = ?
+  AND a.uuid > ?
+  AND a.deleted_at IS NULL
+ORDER BY
+  a.uuid
+LIMIT
+  ?
+`
+
+type ListAuthorsByBookParams struct {
+	BookUuid  binuuid.UUID
+	AfterUuid binuuid.UUID
+	Limit     int32
+}
+
+func (q *Queries) ListAuthorsByBook(ctx context.Context, arg ListAuthorsByBookParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsByBook, arg.BookUuid, arg.AfterUuid, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.Name,
+			&i.Bio,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksByAuthor = `-- name: ListBooksByAuthor :many
+SELECT
+  b.title, b.uuid, b.publisher_uuid, b.created_at, b.updated_at, b.deleted_at, b.version
+FROM
+  author_books AS ab
+  INNER JOIN books AS b ON ab.book_uuid = b.uuid
+WHERE
+  ab.author_uuid = ?
+  AND b.uuid > ?
+  AND b.deleted_at IS NULL
+ORDER BY
+  b.uuid
+LIMIT
+  ?
+`
+
+type ListBooksByAuthorParams struct {
+	AuthorUuid binuuid.UUID
+	AfterUuid  binuuid.UUID
+	Limit      int32
+}
+
+func (q *Queries) ListBooksByAuthor(ctx context.Context, arg ListBooksByAuthorParams) ([]Book, error) {
+	rows, err := q.db.QueryContext(ctx, listBooksByAuthor, arg.AuthorUuid, arg.AfterUuid, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.Title,
+			&i.Uuid,
+			&i.PublisherUuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeAuthorBooksOfAuthors = `-- name: PurgeAuthorBooksOfAuthors :execrows
+DELETE FROM author_books
+WHERE
+  author_uuid IN (
+    SELECT
+      authors.uuid
+    FROM
+      authors
+    WHERE
+      authors.deleted_at < CAST(? AS DATETIME(6))
+  )
+`
+
+func (q *Queries) PurgeAuthorBooksOfAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeAuthorBooksOfAuthors, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeAuthorBooksOfBooks = `-- name: PurgeAuthorBooksOfBooks :execrows
+DELETE FROM author_books
+WHERE
+  book_uuid IN (
+    SELECT
+      books.uuid
+    FROM
+      books
+    WHERE
+      books.deleted_at < CAST(? AS DATETIME(6))
+  )
+`
+
+func (q *Queries) PurgeAuthorBooksOfBooks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeAuthorBooksOfBooks, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
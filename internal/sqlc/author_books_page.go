@@ -0,0 +1,92 @@
+package sqlc
+
+// ListAuthorBooksPage is written by hand because sqlc does not recognize
+// parameters inside a row constructor, which the keyset predicate needs so
+// that MySQL can seek on the (author_uuid, book_uuid) primary key. It
+// follows the shape of the generated code so that it works with New and
+// WithTx alike.
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+const listAuthorBooksPage = `-- name: ListAuthorBooksPage :many
+SELECT
+  a.uuid AS author_uuid,
+  a.name AS author_name,
+  a.bio AS author_bio,
+  b.uuid AS book_uuid,
+  b.title AS book_title
+FROM
+  author_books AS ab
+  INNER JOIN authors AS a ON ab.author_uuid = a.uuid
+  INNER JOIN books AS b ON ab.book_uuid = b.uuid
+WHERE
+  (ab.author_uuid, ab.book_uuid) > (?, ?)
+  AND ab.created_at >= ?
+  AND ab.created_at < ?
+  AND a.deleted_at IS NULL
+  AND b.deleted_at IS NULL
+ORDER BY
+  ab.author_uuid,
+  ab.book_uuid
+LIMIT
+  ?
+`
+
+type ListAuthorBooksPageParams struct {
+	AfterAuthorUuid binuuid.UUID
+	AfterBookUuid   binuuid.UUID
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	Limit           int32
+}
+
+type ListAuthorBooksPageRow struct {
+	AuthorUuid binuuid.UUID
+	AuthorName string
+	AuthorBio  sql.NullString
+	BookUuid   binuuid.UUID
+	BookTitle  string
+}
+
+// ListAuthorBooksPage returns the live author-book links that sort after
+// (arg.AfterAuthorUuid, arg.AfterBookUuid), in primary key order.
+func (q *Queries) ListAuthorBooksPage(ctx context.Context, arg ListAuthorBooksPageParams) ([]ListAuthorBooksPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorBooksPage,
+		arg.AfterAuthorUuid,
+		arg.AfterBookUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAuthorBooksPageRow
+	for rows.Next() {
+		var i ListAuthorBooksPageRow
+		if err := rows.Scan(
+			&i.AuthorUuid,
+			&i.AuthorName,
+			&i.AuthorBio,
+			&i.BookUuid,
+			&i.BookTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
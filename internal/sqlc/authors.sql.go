@@ -8,8 +8,9 @@ package sqlc
 import (
 	"context"
 	"database/sql"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 )
 
 const createAuthor = `-- name: CreateAuthor :exec
@@ -20,7 +21,7 @@ VALUES
 `
 
 type CreateAuthorParams struct {
-	Uuid uuid.UUID
+	Uuid binuuid.UUID
 	Name string
 	Bio  sql.NullString
 }
@@ -30,40 +31,84 @@ func (q *Queries) CreateAuthor(ctx context.Context, arg CreateAuthorParams) erro
 	return err
 }
 
-const deleteAuthor = `-- name: DeleteAuthor :exec
-DELETE FROM authors
+const deleteAuthor = `-- name: DeleteAuthor :execrows
+UPDATE authors
+SET
+  deleted_at = CURRENT_TIMESTAMP(6),
+  version = version + 1
 WHERE
   uuid = ?
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteAuthor(ctx context.Context, argUuid uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteAuthor, argUuid)
-	return err
+func (q *Queries) DeleteAuthor(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuthor, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getAuthor = `-- name: GetAuthor :one
 SELECT
-  uuid, name, bio
+  name, bio, uuid, created_at, updated_at, deleted_at, version
 FROM
   authors
 WHERE
   uuid = ?
+  AND deleted_at IS NULL
 LIMIT
   1
 `
 
-func (q *Queries) GetAuthor(ctx context.Context, argUuid uuid.UUID) (Author, error) {
-	row := q.db.QueryRowContext(ctx, getAuthor, argUuid)
+func (q *Queries) GetAuthor(ctx context.Context, uuid binuuid.UUID) (Author, error) {
+	row := q.db.QueryRowContext(ctx, getAuthor, uuid)
 	var i Author
-	err := row.Scan(&i.Uuid, &i.Name, &i.Bio)
+	err := row.Scan(
+		&i.Name,
+		&i.Bio,
+		&i.Uuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getAuthorIncludingDeleted = `-- name: GetAuthorIncludingDeleted :one
+SELECT
+  name, bio, uuid, created_at, updated_at, deleted_at, version
+FROM
+  authors
+WHERE
+  uuid = ?
+LIMIT
+  1
+`
+
+func (q *Queries) GetAuthorIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (Author, error) {
+	row := q.db.QueryRowContext(ctx, getAuthorIncludingDeleted, uuid)
+	var i Author
+	err := row.Scan(
+		&i.Name,
+		&i.Bio,
+		&i.Uuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
 	return i, err
 }
 
 const listAuthors = `-- name: ListAuthors :many
 SELECT
-  uuid, name, bio
+  name, bio, uuid, created_at, updated_at, deleted_at, version
 FROM
   authors
+WHERE
+  deleted_at IS NULL
 ORDER BY
   uuid
 `
@@ -77,7 +122,15 @@ func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
 	var items []Author
 	for rows.Next() {
 		var i Author
-		if err := rows.Scan(&i.Uuid, &i.Name, &i.Bio); err != nil {
+		if err := rows.Scan(
+			&i.Name,
+			&i.Bio,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -91,22 +144,195 @@ func (q *Queries) ListAuthors(ctx context.Context) ([]Author, error) {
 	return items, nil
 }
 
-const updateAuthor = `-- name: UpdateAuthor :exec
+const listAuthorsPage = `-- name: ListAuthorsPage :many
+SELECT
+  name, bio, uuid, created_at, updated_at, deleted_at, version
+FROM
+  authors
+WHERE
+  uuid > ?
+  AND created_at >= ?
+  AND created_at < ?
+  AND updated_at >= ?
+  AND updated_at < ?
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  ?
+`
+
+type ListAuthorsPageParams struct {
+	AfterUuid   binuuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Limit       int32
+}
+
+func (q *Queries) ListAuthorsPage(ctx context.Context, arg ListAuthorsPageParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsPage,
+		arg.AfterUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.Name,
+			&i.Bio,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuthorsPageIncludingDeleted = `-- name: ListAuthorsPageIncludingDeleted :many
+SELECT
+  name, bio, uuid, created_at, updated_at, deleted_at, version
+FROM
+  authors
+WHERE
+  uuid > ?
+  AND created_at >= ?
+  AND created_at < ?
+  AND updated_at >= ?
+  AND updated_at < ?
+ORDER BY
+  uuid
+LIMIT
+  ?
+`
+
+type ListAuthorsPageIncludingDeletedParams struct {
+	AfterUuid   binuuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Limit       int32
+}
+
+func (q *Queries) ListAuthorsPageIncludingDeleted(ctx context.Context, arg ListAuthorsPageIncludingDeletedParams) ([]Author, error) {
+	rows, err := q.db.QueryContext(ctx, listAuthorsPageIncludingDeleted,
+		arg.AfterUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Author
+	for rows.Next() {
+		var i Author
+		if err := rows.Scan(
+			&i.Name,
+			&i.Bio,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeAuthors = `-- name: PurgeAuthors :execrows
+DELETE FROM authors
+WHERE
+  deleted_at < CAST(? AS DATETIME(6))
+`
+
+func (q *Queries) PurgeAuthors(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeAuthors, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreAuthor = `-- name: RestoreAuthor :execrows
+UPDATE authors
+SET
+  deleted_at = NULL,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreAuthor(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreAuthor, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateAuthor = `-- name: UpdateAuthor :execrows
 UPDATE authors
 SET
   name = ?,
-  bio = ?
+  bio = ?,
+  version = version + 1
 WHERE
   uuid = ?
+  AND version = ?
+  AND deleted_at IS NULL
 `
 
 type UpdateAuthorParams struct {
-	Name string
-	Bio  sql.NullString
-	Uuid uuid.UUID
+	Name    string
+	Bio     sql.NullString
+	Uuid    binuuid.UUID
+	Version uint32
 }
 
-func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) error {
-	_, err := q.db.ExecContext(ctx, updateAuthor, arg.Name, arg.Bio, arg.Uuid)
-	return err
+func (q *Queries) UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateAuthor,
+		arg.Name,
+		arg.Bio,
+		arg.Uuid,
+		arg.Version,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -7,8 +7,10 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 )
 
 const createBook = `-- name: CreateBook :exec
@@ -19,9 +21,9 @@ VALUES
 `
 
 type CreateBookParams struct {
-	Uuid          uuid.UUID
+	Uuid          binuuid.UUID
 	Title         string
-	PublisherUuid uuid.UUID
+	PublisherUuid binuuid.UUID
 }
 
 func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) error {
@@ -29,32 +31,74 @@ func (q *Queries) CreateBook(ctx context.Context, arg CreateBookParams) error {
 	return err
 }
 
-const deleteBook = `-- name: DeleteBook :exec
-DELETE FROM books
+const deleteBook = `-- name: DeleteBook :execrows
+UPDATE books
+SET
+  deleted_at = CURRENT_TIMESTAMP(6),
+  version = version + 1
 WHERE
   uuid = ?
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteBook(ctx context.Context, argUuid uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deleteBook, argUuid)
-	return err
+func (q *Queries) DeleteBook(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteBook, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getBook = `-- name: GetBook :one
 SELECT
-  uuid, title, publisher_uuid
+  title, uuid, publisher_uuid, created_at, updated_at, deleted_at, version
 FROM
   books
 WHERE
   uuid = ?
+  AND deleted_at IS NULL
 LIMIT
   1
 `
 
-func (q *Queries) GetBook(ctx context.Context, argUuid uuid.UUID) (Book, error) {
-	row := q.db.QueryRowContext(ctx, getBook, argUuid)
+func (q *Queries) GetBook(ctx context.Context, uuid binuuid.UUID) (Book, error) {
+	row := q.db.QueryRowContext(ctx, getBook, uuid)
 	var i Book
-	err := row.Scan(&i.Uuid, &i.Title, &i.PublisherUuid)
+	err := row.Scan(
+		&i.Title,
+		&i.Uuid,
+		&i.PublisherUuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
+const getBookIncludingDeleted = `-- name: GetBookIncludingDeleted :one
+SELECT
+  title, uuid, publisher_uuid, created_at, updated_at, deleted_at, version
+FROM
+  books
+WHERE
+  uuid = ?
+LIMIT
+  1
+`
+
+func (q *Queries) GetBookIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (Book, error) {
+	row := q.db.QueryRowContext(ctx, getBookIncludingDeleted, uuid)
+	var i Book
+	err := row.Scan(
+		&i.Title,
+		&i.Uuid,
+		&i.PublisherUuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
 	return i, err
 }
 
@@ -69,19 +113,21 @@ FROM
   INNER JOIN publishers AS p ON b.publisher_uuid = p.uuid
 WHERE
   b.uuid = ?
+  AND b.deleted_at IS NULL
+  AND p.deleted_at IS NULL
 LIMIT
   1
 `
 
 type GetBookPublisherRow struct {
-	BookUuid      uuid.UUID
+	BookUuid      binuuid.UUID
 	BookTitle     string
-	PublisherUuid uuid.UUID
+	PublisherUuid binuuid.UUID
 	PublisherName string
 }
 
-func (q *Queries) GetBookPublisher(ctx context.Context, argUuid uuid.UUID) (GetBookPublisherRow, error) {
-	row := q.db.QueryRowContext(ctx, getBookPublisher, argUuid)
+func (q *Queries) GetBookPublisher(ctx context.Context, uuid binuuid.UUID) (GetBookPublisherRow, error) {
+	row := q.db.QueryRowContext(ctx, getBookPublisher, uuid)
 	var i GetBookPublisherRow
 	err := row.Scan(
 		&i.BookUuid,
@@ -92,11 +138,77 @@ func (q *Queries) GetBookPublisher(ctx context.Context, argUuid uuid.UUID) (GetB
 	return i, err
 }
 
+const listBookSummariesPage = `-- name: ListBookSummariesPage :many
+SELECT
+  b.title, b.uuid, b.publisher_uuid, b.created_at, b.updated_at, b.deleted_at, b.version,
+  p.name AS publisher_name
+FROM
+  books AS b
+  INNER JOIN publishers AS p ON b.publisher_uuid = p.uuid
+WHERE
+  b.uuid > ?
+  AND b.deleted_at IS NULL
+ORDER BY
+  b.uuid
+LIMIT
+  ?
+`
+
+type ListBookSummariesPageParams struct {
+	AfterUuid binuuid.UUID
+	Limit     int32
+}
+
+type ListBookSummariesPageRow struct {
+	Title         string
+	Uuid          binuuid.UUID
+	PublisherUuid binuuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     sql.NullTime
+	Version       uint32
+	PublisherName string
+}
+
+func (q *Queries) ListBookSummariesPage(ctx context.Context, arg ListBookSummariesPageParams) ([]ListBookSummariesPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBookSummariesPage, arg.AfterUuid, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBookSummariesPageRow
+	for rows.Next() {
+		var i ListBookSummariesPageRow
+		if err := rows.Scan(
+			&i.Title,
+			&i.Uuid,
+			&i.PublisherUuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+			&i.PublisherName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listBooks = `-- name: ListBooks :many
 SELECT
-  uuid, title, publisher_uuid
+  title, uuid, publisher_uuid, created_at, updated_at, deleted_at, version
 FROM
   books
+WHERE
+  deleted_at IS NULL
 ORDER BY
   uuid
 `
@@ -110,7 +222,144 @@ func (q *Queries) ListBooks(ctx context.Context) ([]Book, error) {
 	var items []Book
 	for rows.Next() {
 		var i Book
-		if err := rows.Scan(&i.Uuid, &i.Title, &i.PublisherUuid); err != nil {
+		if err := rows.Scan(
+			&i.Title,
+			&i.Uuid,
+			&i.PublisherUuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksPage = `-- name: ListBooksPage :many
+SELECT
+  title, uuid, publisher_uuid, created_at, updated_at, deleted_at, version
+FROM
+  books
+WHERE
+  uuid > ?
+  AND created_at >= ?
+  AND created_at < ?
+  AND updated_at >= ?
+  AND updated_at < ?
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  ?
+`
+
+type ListBooksPageParams struct {
+	AfterUuid   binuuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Limit       int32
+}
+
+func (q *Queries) ListBooksPage(ctx context.Context, arg ListBooksPageParams) ([]Book, error) {
+	rows, err := q.db.QueryContext(ctx, listBooksPage,
+		arg.AfterUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.Title,
+			&i.Uuid,
+			&i.PublisherUuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBooksPageIncludingDeleted = `-- name: ListBooksPageIncludingDeleted :many
+SELECT
+  title, uuid, publisher_uuid, created_at, updated_at, deleted_at, version
+FROM
+  books
+WHERE
+  uuid > ?
+  AND created_at >= ?
+  AND created_at < ?
+  AND updated_at >= ?
+  AND updated_at < ?
+ORDER BY
+  uuid
+LIMIT
+  ?
+`
+
+type ListBooksPageIncludingDeletedParams struct {
+	AfterUuid   binuuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Limit       int32
+}
+
+func (q *Queries) ListBooksPageIncludingDeleted(ctx context.Context, arg ListBooksPageIncludingDeletedParams) ([]Book, error) {
+	rows, err := q.db.QueryContext(ctx, listBooksPageIncludingDeleted,
+		arg.AfterUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Book
+	for rows.Next() {
+		var i Book
+		if err := rows.Scan(
+			&i.Title,
+			&i.Uuid,
+			&i.PublisherUuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -124,20 +373,81 @@ func (q *Queries) ListBooks(ctx context.Context) ([]Book, error) {
 	return items, nil
 }
 
-const updateBook = `-- name: UpdateBook :exec
+const purgeBooks = `-- name: PurgeBooks :execrows
+DELETE FROM books
+WHERE
+  deleted_at < CAST(? AS DATETIME(6))
+`
+
+func (q *Queries) PurgeBooks(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeBooks, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const reassignBooks = `-- name: ReassignBooks :execrows
 UPDATE books
 SET
-  title = ?
+  publisher_uuid = ?,
+  version = version + 1
+WHERE
+  publisher_uuid = ?
+`
+
+type ReassignBooksParams struct {
+	ToPublisherUuid   binuuid.UUID
+	FromPublisherUuid binuuid.UUID
+}
+
+func (q *Queries) ReassignBooks(ctx context.Context, arg ReassignBooksParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, reassignBooks, arg.ToPublisherUuid, arg.FromPublisherUuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreBook = `-- name: RestoreBook :execrows
+UPDATE books
+SET
+  deleted_at = NULL,
+  version = version + 1
 WHERE
   uuid = ?
+  AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreBook(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreBook, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateBook = `-- name: UpdateBook :execrows
+UPDATE books
+SET
+  title = ?,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND version = ?
+  AND deleted_at IS NULL
 `
 
 type UpdateBookParams struct {
-	Title string
-	Uuid  uuid.UUID
+	Title   string
+	Uuid    binuuid.UUID
+	Version uint32
 }
 
-func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) error {
-	_, err := q.db.ExecContext(ctx, updateBook, arg.Title, arg.Uuid)
-	return err
+func (q *Queries) UpdateBook(ctx context.Context, arg UpdateBookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateBook, arg.Title, arg.Uuid, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -6,28 +6,43 @@ package sqlc
 
 import (
 	"database/sql"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 )
 
 type Author struct {
-	Uuid uuid.UUID
-	Name string
-	Bio  sql.NullString
+	Name      string
+	Bio       sql.NullString
+	Uuid      binuuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+	Version   uint32
 }
 
 type AuthorBook struct {
-	AuthorUuid uuid.UUID
-	BookUuid   uuid.UUID
+	AuthorUuid binuuid.UUID
+	BookUuid   binuuid.UUID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
 }
 
 type Book struct {
-	Uuid          uuid.UUID
 	Title         string
-	PublisherUuid uuid.UUID
+	Uuid          binuuid.UUID
+	PublisherUuid binuuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	DeletedAt     sql.NullTime
+	Version       uint32
 }
 
 type Publisher struct {
-	Uuid uuid.UUID
-	Name string
+	Name      string
+	Uuid      binuuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt sql.NullTime
+	Version   uint32
 }
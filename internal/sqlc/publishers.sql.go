@@ -7,10 +7,28 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 )
 
+const countBooksByPublisher = `-- name: CountBooksByPublisher :one
+SELECT
+  COUNT(*)
+FROM
+  books
+WHERE
+  publisher_uuid = ?
+  AND deleted_at IS NULL
+`
+
+func (q *Queries) CountBooksByPublisher(ctx context.Context, publisherUuid binuuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countBooksByPublisher, publisherUuid)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createPublisher = `-- name: CreatePublisher :exec
 INSERT INTO
   publishers (uuid, name)
@@ -19,7 +37,7 @@ VALUES
 `
 
 type CreatePublisherParams struct {
-	Uuid uuid.UUID
+	Uuid binuuid.UUID
 	Name string
 }
 
@@ -28,32 +46,47 @@ func (q *Queries) CreatePublisher(ctx context.Context, arg CreatePublisherParams
 	return err
 }
 
-const deletePublisher = `-- name: DeletePublisher :exec
-DELETE FROM publishers
+const deletePublisher = `-- name: DeletePublisher :execrows
+UPDATE publishers
+SET
+  deleted_at = CURRENT_TIMESTAMP(6),
+  version = version + 1
 WHERE
   uuid = ?
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) DeletePublisher(ctx context.Context, argUuid uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, deletePublisher, argUuid)
-	return err
+func (q *Queries) DeletePublisher(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePublisher, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getPublisher = `-- name: GetPublisher :one
 SELECT
-  uuid, name
+  name, uuid, created_at, updated_at, deleted_at, version
 FROM
   publishers
 WHERE
   uuid = ?
+  AND deleted_at IS NULL
 LIMIT
   1
 `
 
-func (q *Queries) GetPublisher(ctx context.Context, argUuid uuid.UUID) (Publisher, error) {
-	row := q.db.QueryRowContext(ctx, getPublisher, argUuid)
+func (q *Queries) GetPublisher(ctx context.Context, uuid binuuid.UUID) (Publisher, error) {
+	row := q.db.QueryRowContext(ctx, getPublisher, uuid)
 	var i Publisher
-	err := row.Scan(&i.Uuid, &i.Name)
+	err := row.Scan(
+		&i.Name,
+		&i.Uuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
 	return i, err
 }
 
@@ -68,20 +101,22 @@ FROM
   INNER JOIN books AS b ON p.uuid = b.publisher_uuid
 WHERE
   p.uuid = ?
+  AND p.deleted_at IS NULL
+  AND b.deleted_at IS NULL
 ORDER BY
   p.uuid,
   b.uuid
 `
 
 type GetPublisherBooksRow struct {
-	PublisherUuid uuid.UUID
+	PublisherUuid binuuid.UUID
 	PublisherName string
-	BookUuid      uuid.UUID
+	BookUuid      binuuid.UUID
 	BookTitle     string
 }
 
-func (q *Queries) GetPublisherBooks(ctx context.Context, argUuid uuid.UUID) ([]GetPublisherBooksRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPublisherBooks, argUuid)
+func (q *Queries) GetPublisherBooks(ctx context.Context, uuid binuuid.UUID) ([]GetPublisherBooksRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPublisherBooks, uuid)
 	if err != nil {
 		return nil, err
 	}
@@ -108,11 +143,38 @@ func (q *Queries) GetPublisherBooks(ctx context.Context, argUuid uuid.UUID) ([]G
 	return items, nil
 }
 
+const getPublisherIncludingDeleted = `-- name: GetPublisherIncludingDeleted :one
+SELECT
+  name, uuid, created_at, updated_at, deleted_at, version
+FROM
+  publishers
+WHERE
+  uuid = ?
+LIMIT
+  1
+`
+
+func (q *Queries) GetPublisherIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (Publisher, error) {
+	row := q.db.QueryRowContext(ctx, getPublisherIncludingDeleted, uuid)
+	var i Publisher
+	err := row.Scan(
+		&i.Name,
+		&i.Uuid,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.Version,
+	)
+	return i, err
+}
+
 const listPublishers = `-- name: ListPublishers :many
 SELECT
-  uuid, name
+  name, uuid, created_at, updated_at, deleted_at, version
 FROM
   publishers
+WHERE
+  deleted_at IS NULL
 ORDER BY
   uuid
 `
@@ -126,7 +188,122 @@ func (q *Queries) ListPublishers(ctx context.Context) ([]Publisher, error) {
 	var items []Publisher
 	for rows.Next() {
 		var i Publisher
-		if err := rows.Scan(&i.Uuid, &i.Name); err != nil {
+		if err := rows.Scan(
+			&i.Name,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishersByName = `-- name: ListPublishersByName :many
+SELECT
+  name, uuid, created_at, updated_at, deleted_at, version
+FROM
+  publishers
+WHERE
+  name = ?
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  2
+`
+
+func (q *Queries) ListPublishersByName(ctx context.Context, name string) ([]Publisher, error) {
+	rows, err := q.db.QueryContext(ctx, listPublishersByName, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.Name,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublishersPage = `-- name: ListPublishersPage :many
+SELECT
+  name, uuid, created_at, updated_at, deleted_at, version
+FROM
+  publishers
+WHERE
+  uuid > ?
+  AND created_at >= ?
+  AND created_at < ?
+  AND updated_at >= ?
+  AND updated_at < ?
+  AND deleted_at IS NULL
+ORDER BY
+  uuid
+LIMIT
+  ?
+`
+
+type ListPublishersPageParams struct {
+	AfterUuid   binuuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Limit       int32
+}
+
+func (q *Queries) ListPublishersPage(ctx context.Context, arg ListPublishersPageParams) ([]Publisher, error) {
+	rows, err := q.db.QueryContext(ctx, listPublishersPage,
+		arg.AfterUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.Name,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -140,20 +317,130 @@ func (q *Queries) ListPublishers(ctx context.Context) ([]Publisher, error) {
 	return items, nil
 }
 
-const updatePublisher = `-- name: UpdatePublisher :exec
+const listPublishersPageIncludingDeleted = `-- name: ListPublishersPageIncludingDeleted :many
+SELECT
+  name, uuid, created_at, updated_at, deleted_at, version
+FROM
+  publishers
+WHERE
+  uuid > ?
+  AND created_at >= ?
+  AND created_at < ?
+  AND updated_at >= ?
+  AND updated_at < ?
+ORDER BY
+  uuid
+LIMIT
+  ?
+`
+
+type ListPublishersPageIncludingDeletedParams struct {
+	AfterUuid   binuuid.UUID
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	UpdatedFrom time.Time
+	UpdatedTo   time.Time
+	Limit       int32
+}
+
+func (q *Queries) ListPublishersPageIncludingDeleted(ctx context.Context, arg ListPublishersPageIncludingDeletedParams) ([]Publisher, error) {
+	rows, err := q.db.QueryContext(ctx, listPublishersPageIncludingDeleted,
+		arg.AfterUuid,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.UpdatedFrom,
+		arg.UpdatedTo,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Publisher
+	for rows.Next() {
+		var i Publisher
+		if err := rows.Scan(
+			&i.Name,
+			&i.Uuid,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.Version,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgePublishers = `-- name: PurgePublishers :execrows
+DELETE FROM publishers
+WHERE
+  publishers.deleted_at < CAST(? AS DATETIME(6))
+  AND NOT EXISTS (
+    SELECT
+      1
+    FROM
+      books
+    WHERE
+      books.publisher_uuid = publishers.uuid
+  )
+`
+
+func (q *Queries) PurgePublishers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgePublishers, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restorePublisher = `-- name: RestorePublisher :execrows
 UPDATE publishers
 SET
-  name = ?
+  deleted_at = NULL,
+  version = version + 1
+WHERE
+  uuid = ?
+  AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestorePublisher(ctx context.Context, uuid binuuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restorePublisher, uuid)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updatePublisher = `-- name: UpdatePublisher :execrows
+UPDATE publishers
+SET
+  name = ?,
+  version = version + 1
 WHERE
   uuid = ?
+  AND version = ?
+  AND deleted_at IS NULL
 `
 
 type UpdatePublisherParams struct {
-	Name string
-	Uuid uuid.UUID
+	Name    string
+	Uuid    binuuid.UUID
+	Version uint32
 }
 
-func (q *Queries) UpdatePublisher(ctx context.Context, arg UpdatePublisherParams) error {
-	_, err := q.db.ExecContext(ctx, updatePublisher, arg.Name, arg.Uuid)
-	return err
+func (q *Queries) UpdatePublisher(ctx context.Context, arg UpdatePublisherParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updatePublisher, arg.Name, arg.Uuid, arg.Version)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
@@ -0,0 +1,63 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.25.0
+
+package sqlc
+
+import (
+	"context"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+type Querier interface {
+	CountBooksByPublisher(ctx context.Context, publisherUuid binuuid.UUID) (int64, error)
+	CreateAuthor(ctx context.Context, arg CreateAuthorParams) error
+	CreateAuthorBook(ctx context.Context, arg CreateAuthorBookParams) error
+	CreateBook(ctx context.Context, arg CreateBookParams) error
+	CreatePublisher(ctx context.Context, arg CreatePublisherParams) error
+	DeleteAuthor(ctx context.Context, uuid binuuid.UUID) (int64, error)
+	DeleteAuthorBook(ctx context.Context, arg DeleteAuthorBookParams) (int64, error)
+	DeleteAuthorBooksByAuthor(ctx context.Context, authorUuid binuuid.UUID) (int64, error)
+	DeleteBook(ctx context.Context, uuid binuuid.UUID) (int64, error)
+	DeletePublisher(ctx context.Context, uuid binuuid.UUID) (int64, error)
+	GetAuthor(ctx context.Context, uuid binuuid.UUID) (Author, error)
+	GetAuthorBook(ctx context.Context, arg GetAuthorBookParams) (AuthorBook, error)
+	GetAuthorIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (Author, error)
+	GetBook(ctx context.Context, uuid binuuid.UUID) (Book, error)
+	GetBookIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (Book, error)
+	GetBookPublisher(ctx context.Context, uuid binuuid.UUID) (GetBookPublisherRow, error)
+	GetPublisher(ctx context.Context, uuid binuuid.UUID) (Publisher, error)
+	GetPublisherBooks(ctx context.Context, uuid binuuid.UUID) ([]GetPublisherBooksRow, error)
+	GetPublisherIncludingDeleted(ctx context.Context, uuid binuuid.UUID) (Publisher, error)
+	ListAuthorBooks(ctx context.Context) ([]ListAuthorBooksRow, error)
+	ListAuthorNamesOfBooks(ctx context.Context, bookUuids []binuuid.UUID) ([]ListAuthorNamesOfBooksRow, error)
+	ListAuthors(ctx context.Context) ([]Author, error)
+	ListAuthorsByBook(ctx context.Context, arg ListAuthorsByBookParams) ([]Author, error)
+	ListAuthorsPage(ctx context.Context, arg ListAuthorsPageParams) ([]Author, error)
+	ListAuthorsPageIncludingDeleted(ctx context.Context, arg ListAuthorsPageIncludingDeletedParams) ([]Author, error)
+	ListBookSummariesPage(ctx context.Context, arg ListBookSummariesPageParams) ([]ListBookSummariesPageRow, error)
+	ListBooks(ctx context.Context) ([]Book, error)
+	ListBooksByAuthor(ctx context.Context, arg ListBooksByAuthorParams) ([]Book, error)
+	ListBooksPage(ctx context.Context, arg ListBooksPageParams) ([]Book, error)
+	ListBooksPageIncludingDeleted(ctx context.Context, arg ListBooksPageIncludingDeletedParams) ([]Book, error)
+	ListPublishers(ctx context.Context) ([]Publisher, error)
+	ListPublishersByName(ctx context.Context, name string) ([]Publisher, error)
+	ListPublishersPage(ctx context.Context, arg ListPublishersPageParams) ([]Publisher, error)
+	ListPublishersPageIncludingDeleted(ctx context.Context, arg ListPublishersPageIncludingDeletedParams) ([]Publisher, error)
+	PurgeAuthorBooksOfAuthors(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeAuthorBooksOfBooks(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeAuthors(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeBooks(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgePublishers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	ReassignBooks(ctx context.Context, arg ReassignBooksParams) (int64, error)
+	RestoreAuthor(ctx context.Context, uuid binuuid.UUID) (int64, error)
+	RestoreBook(ctx context.Context, uuid binuuid.UUID) (int64, error)
+	RestorePublisher(ctx context.Context, uuid binuuid.UUID) (int64, error)
+	UpdateAuthor(ctx context.Context, arg UpdateAuthorParams) (int64, error)
+	UpdateBook(ctx context.Context, arg UpdateBookParams) (int64, error)
+	UpdatePublisher(ctx context.Context, arg UpdatePublisherParams) (int64, error)
+}
+
+var _ Querier = (*Queries)(nil)
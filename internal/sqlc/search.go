@@ -0,0 +1,155 @@
+package sqlc
+
+// The full-text search queries are written by hand because sqlc does not
+// recognize parameters inside MATCH ... AGAINST. They follow the shape of
+// the generated code so that they work with New and WithTx alike.
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+)
+
+const searchBooks = `-- name: SearchBooks :many
+SELECT
+  b.uuid AS book_uuid,
+  b.title AS book_title,
+  p.uuid AS publisher_uuid,
+  p.name AS publisher_name,
+  MATCH (b.title) AGAINST (? IN BOOLEAN MODE) AS score
+FROM
+  books AS b
+  INNER JOIN publishers AS p ON b.publisher_uuid = p.uuid
+WHERE
+  MATCH (b.title) AGAINST (? IN BOOLEAN MODE)
+  AND b.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+ORDER BY
+  score DESC,
+  b.uuid
+LIMIT
+  ?
+OFFSET
+  ?
+`
+
+type SearchBooksParams struct {
+	// Query is a boolean-mode search expression, e.g. "+go -rust".
+	Query  string
+	Limit  int32
+	Offset int32
+}
+
+type SearchBooksRow struct {
+	BookUuid      binuuid.UUID
+	BookTitle     string
+	PublisherUuid binuuid.UUID
+	PublisherName string
+	Score         float64
+}
+
+// SearchBooks returns the live books whose title matches arg.Query, most
+// relevant first.
+func (q *Queries) SearchBooks(ctx context.Context, arg SearchBooksParams) ([]SearchBooksRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchBooks,
+		arg.Query,
+		arg.Query,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchBooksRow
+	for rows.Next() {
+		var i SearchBooksRow
+		if err := rows.Scan(
+			&i.BookUuid,
+			&i.BookTitle,
+			&i.PublisherUuid,
+			&i.PublisherName,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAuthors = `-- name: SearchAuthors :many
+SELECT
+  uuid,
+  name,
+  bio,
+  MATCH (name, bio) AGAINST (? IN BOOLEAN MODE) AS score
+FROM
+  authors
+WHERE
+  MATCH (name, bio) AGAINST (? IN BOOLEAN MODE)
+  AND deleted_at IS NULL
+ORDER BY
+  score DESC,
+  uuid
+LIMIT
+  ?
+OFFSET
+  ?
+`
+
+type SearchAuthorsParams struct {
+	// Query is a boolean-mode search expression, e.g. "+go -rust".
+	Query  string
+	Limit  int32
+	Offset int32
+}
+
+type SearchAuthorsRow struct {
+	Uuid  binuuid.UUID
+	Name  string
+	Bio   sql.NullString
+	Score float64
+}
+
+// SearchAuthors returns the live authors whose name or bio matches
+// arg.Query, most relevant first.
+func (q *Queries) SearchAuthors(ctx context.Context, arg SearchAuthorsParams) ([]SearchAuthorsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchAuthors,
+		arg.Query,
+		arg.Query,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchAuthorsRow
+	for rows.Next() {
+		var i SearchAuthorsRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.Name,
+			&i.Bio,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,60 @@
+package testdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/ory/dockertest/v3/docker"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/config"
+)
+
+// dockerPool connects to the Docker daemon.
+func dockerPool() (*dockertest.Pool, error) {
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("construct pool: %w", err)
+	}
+
+	pool.MaxWait = 30 * time.Second
+
+	err = pool.Client.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("connect to Docker: %w", err)
+	}
+	return pool, nil
+}
+
+// startDocker runs MySQL in a container of pool and returns cfg pointing at
+// it. The server may still be starting up.
+func startDocker(pool *dockertest.Pool, cfg config.MySQL) (config.MySQL, func() error, error) {
+	runOptions := &dockertest.RunOptions{
+		Repository: "mysql",
+		Tag:        "8.3.0",
+		Env: []string{
+			fmt.Sprintf("MYSQL_DATABASE=%s", cfg.Database),
+			fmt.Sprintf("MYSQL_ROOT_PASSWORD=%s", cfg.RootPassword),
+			fmt.Sprintf("MYSQL_USER=%s", cfg.User),
+			fmt.Sprintf("MYSQL_PASSWORD=%s", cfg.Password),
+			fmt.Sprintf("MYSQL_HOST=%s", cfg.Host),
+			fmt.Sprintf("MYSQL_TCP_PORT=%s", cfg.Port),
+		},
+	}
+
+	resource, err := pool.RunWithOptions(
+		runOptions,
+		func(config *docker.HostConfig) {
+			config.AutoRemove = true
+			config.RestartPolicy = docker.RestartPolicy{
+				Name: "no",
+			}
+		},
+	)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("start resource: %w", err)
+	}
+
+	cfg.Port = resource.GetPort(fmt.Sprintf("%s/tcp", cfg.Port))
+	return cfg, func() error { return pool.Purge(resource) }, nil
+}
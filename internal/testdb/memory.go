@@ -0,0 +1,63 @@
+package testdb
+
+import (
+	"fmt"
+	"net"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	gmsserver "github.com/dolthub/go-mysql-server/server"
+	gmssql "github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/config"
+)
+
+// Settings used by the go-mysql-server backend when TEST_MYSQL_ sets none.
+const (
+	memoryDatabase = "test"
+	memoryUser     = "test"
+)
+
+// memoryDefaults fills in the settings of cfg that the go-mysql-server
+// backend needs and the environment left empty.
+func memoryDefaults(cfg config.MySQL) config.MySQL {
+	if cfg.Database == "" {
+		cfg.Database = memoryDatabase
+	}
+	if cfg.User == "" {
+		cfg.User = memoryUser
+	}
+	return cfg
+}
+
+// startMemory serves an empty database cfg.Database from go-mysql-server in
+// this process and returns cfg pointing at it. The server does not check
+// passwords and has no privilege tables, so New skips the GRANT.
+func startMemory(cfg config.MySQL) (config.MySQL, func() error, error) {
+	// Foreign keys need an index on the referenced primary key, which
+	// in-memory tables only get with native indexes.
+	db := memory.NewDatabase(cfg.Database)
+	db.EnablePrimaryKeyIndexes()
+	// MySQL 8 compares strings case-insensitively by default.
+	db.SetCollation(nil, gmssql.Collation_utf8mb4_0900_ai_ci)
+	provider := memory.NewDBProvider(db)
+	provider.WithOption(memory.NativeIndexProvider(true))
+	engine := sqle.NewDefault(provider)
+
+	srv, err := gmsserver.NewServer(gmsserver.Config{
+		Protocol: "tcp",
+		Address:  "127.0.0.1:0",
+	}, engine, gmssql.NewContext, memory.NewSessionBuilder(provider), nil)
+	if err != nil {
+		return cfg, nil, fmt.Errorf("start go-mysql-server: %w", err)
+	}
+	go srv.Start()
+
+	host, port, err := net.SplitHostPort(srv.Listener.Addr().String())
+	if err != nil {
+		srv.Close()
+		return cfg, nil, err
+	}
+	cfg.Host, cfg.Port = host, port
+	return cfg, srv.Close, nil
+}
@@ -0,0 +1,359 @@
+// Package testdb runs tests against a real MySQL server.
+//
+// Main starts one MySQL container per test binary and migrates a template
+// database with the embedded migrations. New then gives every test a
+// database of its own with a copy of the template schema, so tests may run
+// in parallel and commit without cleaning up after themselves:
+//
+//	func TestMain(m *testing.M) {
+//		testdb.Main(m)
+//	}
+//
+//	func TestSomething(t *testing.T) {
+//		t.Parallel()
+//		db := testdb.New(t)
+//		...
+//	}
+//
+// The server is configured with the TEST_MYSQL_ settings, read from the
+// environment and the .env file at the module root. TEST_MYSQL_SERVER
+// selects it: "docker" requires Docker, "memory" serves the databases from
+// go-mysql-server inside the test binary, and the default uses Docker when
+// it is reachable and go-mysql-server otherwise. Tests of features that
+// go-mysql-server lacks call Unsupported.
+package testdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/config"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/database"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/migration"
+	"github.com/go-sql-driver/mysql"
+	"github.com/ory/dockertest/v3"
+)
+
+// server is the MySQL server started by Main.
+var server *mysqlServer
+
+// databaseSeq numbers the databases created by New.
+var databaseSeq atomic.Uint64
+
+type mysqlServer struct {
+	cfg config.MySQL
+	// admin is connected as root without a default database.
+	admin *sql.DB
+	// schema holds the statements that recreate the template schema in
+	// the current database.
+	schema []string
+	// memory reports a go-mysql-server backend, see Unsupported.
+	memory bool
+}
+
+// Main starts the server, runs the tests of m and exits with their result
+// after stopping it.
+func Main(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	cfg, err := config.Load("TEST_MYSQL_", envFile())
+	if err != nil {
+		log.Fatalf("Could not load config: %s", err)
+	}
+
+	backend, err := config.Getenv("TEST_MYSQL_SERVER", envFile())
+	if err != nil {
+		log.Fatalf("Could not load config: %s", err)
+	}
+	var pool *dockertest.Pool
+	switch backend {
+	case "", "docker":
+		pool, err = dockerPool()
+		if err != nil && backend == "" {
+			log.Printf("Could not use Docker, falling back to go-mysql-server: %s", err)
+			backend = "memory"
+			break
+		}
+		if err != nil {
+			log.Fatalf("Could not use Docker: %s", err)
+		}
+		backend = "docker"
+	case "memory":
+	default:
+		log.Fatalf("Invalid TEST_MYSQL_SERVER %q, want docker or memory", backend)
+	}
+
+	if backend == "memory" {
+		// go-mysql-server needs no setup, so the tests run without any
+		// settings, e.g. a plain go test on a machine without Docker.
+		cfg = memoryDefaults(cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config: %s", err)
+	}
+
+	var stop func() error
+	if backend == "docker" {
+		cfg, stop, err = startDocker(pool, cfg)
+	} else {
+		cfg, stop, err = startMemory(cfg)
+	}
+	if err != nil {
+		log.Fatalf("Could not start MySQL: %s", err)
+	}
+	defer func() {
+		if err := stop(); err != nil {
+			log.Fatalf("Could not stop MySQL: %s", err)
+		}
+	}()
+
+	// go-mysql-server panics when migration 000005 adds the foreign keys
+	// back to the altered tables. Without foreign key checks it does not
+	// look at the tables; New creates them afresh with the keys in place.
+	var vars map[string]string
+	if backend == "memory" {
+		vars = map[string]string{"foreign_key_checks": "0"}
+	}
+
+	var template *sql.DB
+	connect := func() error {
+		var err error
+		template, err = open(rootConfig(cfg, cfg.Database), vars)
+		if err != nil {
+			return err
+		}
+		return template.Ping()
+	}
+	if pool != nil {
+		err = pool.Retry(connect)
+	} else {
+		err = connect()
+	}
+	if err != nil {
+		log.Printf("Could not connect to database: %s", err)
+		return 1
+	}
+	defer template.Close()
+
+	ctx := context.Background()
+	if err := migrate(ctx, template); err != nil {
+		log.Printf("Could not migrate database: %s", err)
+		return 1
+	}
+
+	schema, err := dumpSchema(ctx, template, cfg.Database)
+	if err != nil {
+		log.Printf("Could not read schema: %s", err)
+		return 1
+	}
+
+	admin, err := open(rootConfig(cfg, ""), nil)
+	if err != nil {
+		log.Printf("Could not connect to database: %s", err)
+		return 1
+	}
+	defer admin.Close()
+
+	server = &mysqlServer{cfg: cfg, admin: admin, schema: schema, memory: backend == "memory"}
+	return m.Run()
+}
+
+// New creates an empty database with the migrated schema and returns a
+// connection pool to it, logged in as the TEST_MYSQL_USER. The pool is
+// closed and the database dropped when t ends.
+func New(t testing.TB) *sql.DB {
+	t.Helper()
+
+	if server == nil {
+		t.Fatal("testdb: call testdb.Main from TestMain")
+	}
+
+	ctx := context.Background()
+	name := fmt.Sprintf("%s_%d", server.cfg.Database, databaseSeq.Add(1))
+	if err := server.create(ctx, name); err != nil {
+		t.Fatalf("testdb: create database %s: %s", name, err)
+	}
+
+	cfg := server.cfg
+	cfg.Database = name
+	db, err := open(cfg, nil)
+	if err != nil {
+		t.Fatalf("testdb: connect to database %s: %s", name, err)
+	}
+
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("testdb: close database %s: %s", name, err)
+		}
+		if _, err := server.admin.ExecContext(ctx, "DROP DATABASE "+quote(name)); err != nil {
+			t.Errorf("testdb: drop database %s: %s", name, err)
+		}
+	})
+	return db
+}
+
+// Unsupported skips t when the tests run against go-mysql-server, which
+// does not support feature.
+func Unsupported(t testing.TB, feature string) {
+	t.Helper()
+
+	if server != nil && server.memory {
+		t.Skipf("testdb: go-mysql-server does not support %s", feature)
+	}
+}
+
+// create creates the database name with the template schema and grants the
+// test user access to it.
+func (s *mysqlServer) create(ctx context.Context, name string) error {
+	conn, err := s.admin.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stmts := []string{
+		"CREATE DATABASE " + quote(name),
+		"USE " + quote(name),
+		// The tables are created in name order, so a foreign key may refer
+		// to a table that does not exist yet.
+		"SET FOREIGN_KEY_CHECKS = 0",
+	}
+	stmts = append(stmts, s.schema...)
+	stmts = append(stmts, "SET FOREIGN_KEY_CHECKS = 1")
+	if !s.memory {
+		stmts = append(stmts, fmt.Sprintf("GRANT ALL PRIVILEGES ON %s.* TO %s@'%%'", quote(name), quoteString(s.cfg.User)))
+	}
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("%s: %w", stmt, err)
+		}
+	}
+	return nil
+}
+
+// migrate applies the embedded migrations to db.
+func migrate(ctx context.Context, db *sql.DB) error {
+	m, err := migration.New(ctx, db)
+	if err != nil {
+		return err
+	}
+	return errors.Join(m.Up(), m.Close())
+}
+
+// dumpSchema returns the statements that recreate the tables of schema,
+// including the rows of golang-migrate's version table.
+func dumpSchema(ctx context.Context, db *sql.DB, schema string) ([]string, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = ? AND table_type = 'BASE TABLE'
+		ORDER BY table_name`, schema)
+	if err != nil {
+		return nil, err
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		// go-mysql-server lists the tables behind FULLTEXT indexes, which
+		// it creates along with the indexed table.
+		if strings.Contains(table, "_FTS_") {
+			continue
+		}
+		tables = append(tables, table)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return nil, err
+	}
+
+	stmts := make([]string, 0, len(tables)+1)
+	for _, table := range tables {
+		var name, stmt string
+		if err := db.QueryRowContext(ctx, "SHOW CREATE TABLE "+quote(table)).Scan(&name, &stmt); err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+
+	var version int64
+	var dirty bool
+	if err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty); err != nil {
+		return nil, err
+	}
+	stmts = append(stmts, fmt.Sprintf("INSERT INTO schema_migrations (version, dirty) VALUES (%d, %t)", version, dirty))
+	return stmts, nil
+}
+
+// rootConfig returns cfg logged in as root, which may create databases,
+// with multi-statement queries for the migration files.
+func rootConfig(cfg config.MySQL, database string) config.MySQL {
+	cfg.User = "root"
+	cfg.Password = cfg.RootPassword
+	cfg.Database = database
+	cfg.MultiStatements = true
+	return cfg
+}
+
+// open connects with cfg and sets the session variables vars on every
+// connection.
+func open(cfg config.MySQL, vars map[string]string) (*sql.DB, error) {
+	driverConfig, err := cfg.DriverConfig()
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range vars {
+		if driverConfig.Params == nil {
+			driverConfig.Params = make(map[string]string)
+		}
+		driverConfig.Params[name] = value
+	}
+	connector, err := mysql.NewConnector(driverConfig)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(connector)
+	database.ConfigurePool(db, cfg)
+	return db, nil
+}
+
+// envFile returns the .env file at the module root, so that tests of every
+// package read the same settings.
+func envFile() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return ".env"
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return filepath.Join(dir, ".env")
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ".env"
+		}
+		dir = parent
+	}
+}
+
+// quote quotes a MySQL identifier.
+func quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+// quoteString quotes a MySQL string literal.
+func quoteString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
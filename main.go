@@ -2,57 +2,93 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"strings"
 
-	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/google/uuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/cli"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/config"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/database"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/querylog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/requestid"
 )
 
-func run() error {
-	mysqlDatabase := os.Getenv("MYSQL_DATABASE")
-	mysqlUser := os.Getenv("MYSQL_USER")
-	mysqlPass := os.Getenv("MYSQL_PASSWORD")
-	mysqlHost := os.Getenv("MYSQL_HOST")
-	mysqlPort := os.Getenv("MYSQL_TCP_PORT")
+const usage = `usage: go-sqlc-mysql-sample [mysql flags] <command> [arguments]
+
+commands:
+  author    create|get|list|update|delete
+  publisher create|get|list|update|delete
+  book      create|get|list|update|delete
+  link      add|remove|list
+  serve     run the HTTP API
+  import    load CSV or JSON files
+  export    stream a table as NDJSON, JSON or CSV
+  purge     remove old soft-deleted rows
+  seed      load a deterministic sample catalog
+  migrate   up|down N|goto V|status|force V`
 
-	dataSource := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", mysqlUser, mysqlPass, mysqlHost, mysqlPort, mysqlDatabase)
+// commands are the subcommands besides the catalog commands of package cli.
+var commands = map[string]func(ctx context.Context, db *dbtx.DB, args []string) error{
+	"serve":   serve,
+	"purge":   purge,
+	"seed":    seedCatalog,
+	"import":  importCatalog,
+	"export":  exportCatalog,
+	"migrate": migrateSchema,
+}
 
-	db, err := sql.Open("mysql", dataSource)
+func run() error {
+	cfg, args, err := config.LoadWithFlags("MYSQL_", ".env", os.Args[1:])
 	if err != nil {
 		return err
 	}
 
-	queries := sqlc.New(db)
-
-	ctx := context.Background()
-	authors, err := queries.ListAuthors(ctx)
-	if err != nil {
-		return err
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	command, ok := commands[args[0]]
+	if !ok && !cli.Handles(args[0]) {
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
 	}
-	log.Println(authors)
 
-	authorUuid := uuid.New()
+	if args[0] == "migrate" {
+		// Migration files may hold several statements.
+		cfg.MultiStatements = true
+	}
 
-	err = queries.CreateAuthor(ctx, sqlc.CreateAuthorParams{
-		Uuid: authorUuid,
-		Name: "Brian Kernighan",
-		Bio:  sql.NullString{String: "Co-author of The C Programming Language and The Go Programming Language", Valid: true},
-	})
+	// Tag the queries of this run; serve tags those of every request.
+	ctx := requestid.With(context.Background(), requestid.New())
+	sqlDB, err := database.Open(ctx, cfg)
 	if err != nil {
 		return err
 	}
+	defer sqlDB.Close()
 
-	author, err := queries.GetAuthor(ctx, authorUuid)
-	if err != nil {
-		return err
+	db := dbtx.Wrap(sqlDB, querylog.New(slog.New(slog.NewTextHandler(os.Stderr, nil)), querylog.Options{
+		Level:         cfg.QueryLogLevel,
+		SlowThreshold: cfg.SlowQueryThreshold,
+		Redact:        redactedColumns(cfg.QueryLogRedact),
+	}))
+
+	if ok {
+		return command(ctx, db, args[1:])
 	}
-	log.Println(author)
+	return cli.New(db, os.Stdout).Run(ctx, args)
+}
 
-	return nil
+// redactedColumns splits a comma-separated list of "table.column"s.
+func redactedColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+	return columns
 }
 
 func main() {
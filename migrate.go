@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/migration"
+)
+
+const migrateUsage = "usage: migrate up | down N | goto V | status | force V"
+
+func migrateSchema(ctx context.Context, db *dbtx.DB, args []string) (err error) {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	m, err := migration.New(ctx, db.Unwrap())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = errors.Join(err, m.Close())
+	}()
+	m.SetLogger(log.Printf)
+
+	switch args[0] {
+	case "up":
+		if len(args) != 1 {
+			return errors.New(migrateUsage)
+		}
+		return m.Up()
+	case "down":
+		n, err := migrateArg(args)
+		if err != nil {
+			return err
+		}
+		return m.Down(n)
+	case "goto":
+		v, err := migrateArg(args)
+		if err != nil {
+			return err
+		}
+		return m.Goto(uint(v))
+	case "force":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		v, err := strconv.Atoi(args[1])
+		if err != nil || v < -1 {
+			return fmt.Errorf("force: invalid version %q", args[1])
+		}
+		return m.Force(v)
+	case "status":
+		if len(args) != 1 {
+			return errors.New(migrateUsage)
+		}
+		status, err := m.Status()
+		if err != nil {
+			return err
+		}
+		return printMigrationStatus(status)
+	}
+	return errors.New(migrateUsage)
+}
+
+// migrateArg parses the single non-negative number argument of down and
+// goto.
+func migrateArg(args []string) (int, error) {
+	if len(args) != 2 {
+		return 0, errors.New(migrateUsage)
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s: invalid number %q", args[0], args[1])
+	}
+	return n, nil
+}
+
+func printMigrationStatus(status migration.Status) error {
+	state := "clean"
+	if status.Dirty {
+		state = "dirty, fix the schema and run migrate force"
+	}
+	fmt.Printf("version %d (%s)\n", status.Version, state)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "VERSION\tNAME\tAPPLIED")
+	for _, mig := range status.Migrations {
+		fmt.Fprintf(tw, "%d\t%s\t%t\n", mig.Version, mig.Name, mig.Applied)
+	}
+	return tw.Flush()
+}
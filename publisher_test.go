@@ -6,14 +6,18 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
 	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
-	"github.com/google/uuid"
 )
 
 func TestCreatePublisher(t *testing.T) {
-	publisherUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
@@ -33,8 +37,9 @@ func TestCreatePublisher(t *testing.T) {
 				},
 			},
 			expected: sqlc.Publisher{
-				Uuid: publisherUuid,
-				Name: "publisher001",
+				Uuid:    publisherUuid,
+				Name:    "publisher001",
+				Version: 1,
 			},
 		},
 	}
@@ -71,6 +76,7 @@ func TestCreatePublisher(t *testing.T) {
 				t.Error(err)
 			}
 
+			checkTimestamps(t, &publisher)
 			if publisher != tt.expected {
 				t.Errorf("got=%v, want=%v", publisher, tt.expected)
 			}
@@ -79,7 +85,10 @@ func TestCreatePublisher(t *testing.T) {
 }
 
 func TestUpdatePublisher(t *testing.T) {
-	publisherUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
@@ -100,13 +109,15 @@ func TestUpdatePublisher(t *testing.T) {
 					Name: "publisher001",
 				},
 				updatePublisherParams: sqlc.UpdatePublisherParams{
-					Uuid: publisherUuid,
-					Name: "Updated: publisher001",
+					Uuid:    publisherUuid,
+					Name:    "Updated: publisher001",
+					Version: 1,
 				},
 			},
 			expected: sqlc.Publisher{
-				Uuid: publisherUuid,
-				Name: "Updated: publisher001",
+				Uuid:    publisherUuid,
+				Name:    "Updated: publisher001",
+				Version: 2,
 			},
 		},
 	}
@@ -138,7 +149,7 @@ func TestUpdatePublisher(t *testing.T) {
 			}
 
 			// update publisher
-			err = queries.UpdatePublisher(ctx, tt.input.updatePublisherParams)
+			_, err = queries.UpdatePublisher(ctx, tt.input.updatePublisherParams)
 			if err != nil {
 				t.Error(err)
 			}
@@ -149,6 +160,7 @@ func TestUpdatePublisher(t *testing.T) {
 				t.Error(err)
 			}
 
+			checkTimestamps(t, &publisher)
 			if publisher != tt.expected {
 				t.Errorf("got=%v, want=%v", publisher, tt.expected)
 			}
@@ -157,13 +169,16 @@ func TestUpdatePublisher(t *testing.T) {
 }
 
 func TestDeletePublisher(t *testing.T) {
-	publisherUuid := uuid.New()
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
 
 	tests := []struct {
 		scenario string
 		input    struct {
 			createPublisherParams sqlc.CreatePublisherParams
-			deletePublisherUuid   uuid.UUID
+			deletePublisherUuid   binuuid.UUID
 		}
 		expected error
 	}{
@@ -171,7 +186,7 @@ func TestDeletePublisher(t *testing.T) {
 			scenario: "delete publisher",
 			input: struct {
 				createPublisherParams sqlc.CreatePublisherParams
-				deletePublisherUuid   uuid.UUID
+				deletePublisherUuid   binuuid.UUID
 			}{
 				createPublisherParams: sqlc.CreatePublisherParams{
 					Uuid: publisherUuid,
@@ -210,7 +225,7 @@ func TestDeletePublisher(t *testing.T) {
 			}
 
 			// delete publisher
-			err = queries.DeletePublisher(ctx, tt.input.deletePublisherUuid)
+			_, err = queries.DeletePublisher(ctx, tt.input.deletePublisherUuid)
 			if err != nil {
 				t.Error(err)
 			}
@@ -225,9 +240,12 @@ func TestDeletePublisher(t *testing.T) {
 }
 
 func TestListPublishers(t *testing.T) {
-	publisherUuids := []uuid.UUID{
-		uuid.New(),
-		uuid.New(),
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
 	}
 
 	tests := []struct {
@@ -253,12 +271,14 @@ func TestListPublishers(t *testing.T) {
 			},
 			expected: []sqlc.Publisher{
 				{
-					Uuid: publisherUuids[0],
-					Name: "publisher001",
+					Uuid:    publisherUuids[0],
+					Name:    "publisher001",
+					Version: 1,
 				},
 				{
-					Uuid: publisherUuids[1],
-					Name: "publisher002",
+					Uuid:    publisherUuids[1],
+					Name:    "publisher002",
+					Version: 1,
 				},
 			},
 		},
@@ -306,6 +326,7 @@ func TestListPublishers(t *testing.T) {
 			)
 
 			for i := range publishers {
+				checkTimestamps(t, &publishers[i])
 				if publishers[i] != tt.expected[i] {
 					t.Errorf("got=%v, want=%v", publishers[i].Name, tt.expected[i])
 				}
@@ -315,17 +336,20 @@ func TestListPublishers(t *testing.T) {
 }
 
 func TestGetPublisherBooks(t *testing.T) {
-	publisherUuid := uuid.New()
-	bookUuids := []uuid.UUID{
-		uuid.New(),
-		uuid.New(),
+	t.Parallel()
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuids := []binuuid.UUID{
+		binuuid.New(),
+		binuuid.New(),
 	}
 	tests := []struct {
 		scenario string
 		input    struct {
 			createPublisherParams sqlc.CreatePublisherParams
 			createBookParamsList  []sqlc.CreateBookParams
-			getPublisherBooksUuid uuid.UUID
+			getPublisherBooksUuid binuuid.UUID
 		}
 		expected []sqlc.GetPublisherBooksRow
 	}{
@@ -334,7 +358,7 @@ func TestGetPublisherBooks(t *testing.T) {
 			input: struct {
 				createPublisherParams sqlc.CreatePublisherParams
 				createBookParamsList  []sqlc.CreateBookParams
-				getPublisherBooksUuid uuid.UUID
+				getPublisherBooksUuid binuuid.UUID
 			}{
 				createPublisherParams: sqlc.CreatePublisherParams{
 					Uuid: publisherUuid,
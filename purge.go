@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+)
+
+// defaultPurgeAge is how long soft-deleted rows are kept by default.
+const defaultPurgeAge = 30 * 24 * time.Hour
+
+func purge(ctx context.Context, db *dbtx.DB, args []string) error {
+	fs := flag.NewFlagSet("purge", flag.ContinueOnError)
+	olderThan := fs.Duration("older-than", defaultPurgeAge, "remove rows soft-deleted longer ago than this")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *olderThan < 0 {
+		return errors.New("-older-than must not be negative")
+	}
+
+	result, err := catalog.New(db).Purge(ctx, time.Now().Add(-*olderThan))
+	if err != nil {
+		return err
+	}
+	log.Printf("purged %d authors, %d publishers, %d books and %d author-book links",
+		result.Authors, result.Publishers, result.Books, result.AuthorBooks)
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/querytest"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+// TestQuerierConformance runs the checks that memdb passes against MySQL.
+func TestQuerierConformance(t *testing.T) {
+	t.Parallel()
+
+	querytest.Run(t, func(t *testing.T) sqlc.Querier {
+		return unsupportedQuerier{Queries: sqlc.New(testdb.New(t)), t: t}
+	})
+}
+
+// unsupportedQuerier skips the check using it when it calls a query that
+// testdb.Unsupported rejects.
+type unsupportedQuerier struct {
+	*sqlc.Queries
+	t *testing.T
+}
+
+func (q unsupportedQuerier) PurgePublishers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	testdb.Unsupported(q.t, "correlated subqueries in DELETE")
+	return q.Queries.PurgePublishers(ctx, deletedBefore)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/httpapi"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/querylog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/requestid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestQueryLog(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			requestID string
+		}
+		expected struct {
+			requestID string
+		}
+	}{
+		{
+			scenario: "request id from client",
+			input: struct {
+				requestID string
+			}{
+				requestID: "request001",
+			},
+			expected: struct {
+				requestID string
+			}{
+				requestID: "request001",
+			},
+		},
+		{
+			scenario: "invalid request id replaced",
+			input: struct {
+				requestID string
+			}{
+				requestID: "request 001",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			server := httpapi.New(dbtx.Wrap(db, querylog.New(logger, querylog.Options{
+				Redact: []string{"authors.bio"},
+			})))
+
+			// create author
+			body := fmt.Sprintf(`{"uuid":"%s","name":"author001","bio":"secret001"}`, binuuid.New())
+			req := httptest.NewRequest(http.MethodPost, "/authors", strings.NewReader(body))
+			req.Header.Set(requestid.Header, tt.input.requestID)
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, req)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("got=%v, want=%v", rec.Code, http.StatusCreated)
+			}
+
+			requestID := rec.Header().Get(requestid.Header)
+			if tt.expected.requestID != "" && requestID != tt.expected.requestID {
+				t.Errorf("got=%v, want=%v", requestID, tt.expected.requestID)
+			}
+			if !requestid.Valid(requestID) {
+				t.Errorf("got=%q, want a valid request id", requestID)
+			}
+
+			// the insert is logged with the request id and without the bio
+			if strings.Contains(buf.String(), "secret001") {
+				t.Errorf("got=%v, want the bio redacted", buf.String())
+			}
+			logged := false
+			scanner := bufio.NewScanner(&buf)
+			for scanner.Scan() {
+				var record struct {
+					Query     string `json:"query"`
+					RequestID string `json:"request_id"`
+				}
+				if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+					t.Fatal(err)
+				}
+				if record.Query != "CreateAuthor" {
+					continue
+				}
+				logged = true
+				if record.RequestID != requestID {
+					t.Errorf("got=%v, want=%v", record.RequestID, requestID)
+				}
+			}
+			if !logged {
+				t.Errorf("got=%v, want a CreateAuthor record", buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/binuuid"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/httpapi"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/pagination"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+// The full-text index only sees committed rows, so the search tests commit
+// their data to a database of their own.
+
+func TestSearchBooks(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "boolean-mode full-text search")
+	db := testdb.New(t)
+
+	publisherUuid := binuuid.New()
+	bookUuids := []binuuid.UUID{binuuid.New(), binuuid.New(), binuuid.New()}
+
+	tests := []struct {
+		scenario string
+		input    struct {
+			query string
+			limit int
+		}
+		expected struct {
+			top    string
+			titles []string
+			pages  int
+		}
+	}{
+		{
+			scenario: "single word",
+			input: struct {
+				query string
+				limit int
+			}{
+				query: "gopher",
+				limit: 10,
+			},
+			expected: struct {
+				top    string
+				titles []string
+				pages  int
+			}{
+				titles: []string{"gopher cookbook", "gopher handbook"},
+				pages:  1,
+			},
+		},
+		{
+			scenario: "boolean operators",
+			input: struct {
+				query string
+				limit int
+			}{
+				query: "+cookbook -crab",
+				limit: 10,
+			},
+			expected: struct {
+				top    string
+				titles []string
+				pages  int
+			}{
+				top:    "gopher cookbook",
+				titles: []string{"gopher cookbook"},
+				pages:  1,
+			},
+		},
+		{
+			scenario: "ranked page by page",
+			input: struct {
+				query string
+				limit int
+			}{
+				query: "gopher cookbook",
+				limit: 1,
+			},
+			expected: struct {
+				top    string
+				titles []string
+				pages  int
+			}{
+				top:    "gopher cookbook",
+				titles: []string{"crab cookbook", "gopher cookbook", "gopher handbook"},
+				pages:  3,
+			},
+		},
+	}
+
+	queries := sqlc.New(db)
+	ctx := context.Background()
+
+	// create books
+	err := queries.CreatePublisher(ctx, sqlc.CreatePublisherParams{Uuid: publisherUuid, Name: "publisher001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, title := range []string{"gopher handbook", "gopher cookbook", "crab cookbook"} {
+		err := queries.CreateBook(ctx, sqlc.CreateBookParams{Uuid: bookUuids[i], Title: title, PublisherUuid: publisherUuid})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			service := catalog.New(db)
+
+			// search books page by page
+			var items []sqlc.SearchBooksRow
+			pages := 0
+			req := pagination.Request{Limit: tt.input.limit}
+			for {
+				page, err := service.SearchBooks(ctx, tt.input.query, req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				items = append(items, page.Items...)
+				pages++
+				if page.NextCursor == "" || pages > len(bookUuids) {
+					break
+				}
+				req.Cursor = page.NextCursor
+			}
+
+			if pages != tt.expected.pages {
+				t.Errorf("got=%v, want=%v", pages, tt.expected.pages)
+			}
+			if len(items) != len(tt.expected.titles) {
+				t.Fatalf("got=%v, want=%v", items, tt.expected.titles)
+			}
+			if tt.expected.top != "" && items[0].BookTitle != tt.expected.top {
+				t.Errorf("got=%v, want=%v", items[0].BookTitle, tt.expected.top)
+			}
+
+			titles := make([]string, 0, len(items))
+			for i, item := range items {
+				if i > 0 && item.Score > items[i-1].Score {
+					t.Errorf("got score %v after %v", item.Score, items[i-1].Score)
+				}
+				if item.PublisherName != "publisher001" {
+					t.Errorf("got=%v, want=%v", item.PublisherName, "publisher001")
+				}
+				titles = append(titles, item.BookTitle)
+			}
+			sort.Strings(titles)
+			for i := range titles {
+				if titles[i] != tt.expected.titles[i] {
+					t.Errorf("got=%v, want=%v", titles[i], tt.expected.titles[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSearchAuthors(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "boolean-mode full-text search")
+	db := testdb.New(t)
+
+	authorUuids := []binuuid.UUID{binuuid.New(), binuuid.New()}
+
+	tests := []struct {
+		scenario string
+		input    string
+		expected []binuuid.UUID
+	}{
+		{
+			scenario: "match name",
+			input:    "kernighan",
+			expected: []binuuid.UUID{authorUuids[0]},
+		},
+		{
+			scenario: "match bio",
+			input:    "unix",
+			expected: []binuuid.UUID{authorUuids[1]},
+		},
+		{
+			scenario: "no match",
+			input:    "cobol",
+			expected: []binuuid.UUID{},
+		},
+	}
+
+	queries := sqlc.New(db)
+	ctx := context.Background()
+
+	// create authors
+	for _, params := range []sqlc.CreateAuthorParams{
+		{Uuid: authorUuids[0], Name: "brian kernighan", Bio: sql.NullString{String: "awk and c", Valid: true}},
+		{Uuid: authorUuids[1], Name: "ken thompson", Bio: sql.NullString{String: "unix and plan9", Valid: true}},
+	} {
+		err := queries.CreateAuthor(ctx, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			service := catalog.New(db)
+
+			// search authors
+			page, err := service.SearchAuthors(ctx, tt.input, pagination.Request{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(page.Items) != len(tt.expected) {
+				t.Fatalf("got=%v, want=%v", page.Items, tt.expected)
+			}
+			for i := range page.Items {
+				if page.Items[i].Uuid != tt.expected[i] {
+					t.Errorf("got=%v, want=%v", page.Items[i].Uuid, tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSearchInvalidQuery(t *testing.T) {
+	t.Parallel()
+	testdb.Unsupported(t, "boolean-mode full-text search")
+	db := testdb.New(t)
+
+	tests := []struct {
+		scenario string
+		input    string
+		expected struct {
+			status int
+			body   string
+		}
+	}{
+		{
+			scenario: "lone at sign",
+			input:    "@",
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusBadRequest,
+				body:   `{"error":"invalid search query"}`,
+			},
+		},
+		{
+			scenario: "unbalanced parenthesis",
+			input:    "(gopher",
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusBadRequest,
+				body:   `{"error":"invalid search query"}`,
+			},
+		},
+		{
+			scenario: "trailing minus",
+			input:    "gopher -",
+			expected: struct {
+				status int
+				body   string
+			}{
+				status: http.StatusBadRequest,
+				body:   `{"error":"invalid search query"}`,
+			},
+		},
+	}
+
+	server := httpapi.New(db)
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			t.Parallel()
+
+			for _, path := range []string{"/search/books", "/search/authors"} {
+				req := httptest.NewRequest(http.MethodGet, path+"?q="+url.QueryEscape(tt.input), nil)
+				rec := httptest.NewRecorder()
+				server.ServeHTTP(rec, req)
+
+				if rec.Code != tt.expected.status {
+					t.Errorf("got=%v, want=%v", rec.Code, tt.expected.status)
+				}
+				if body := strings.TrimSpace(rec.Body.String()); body != tt.expected.body {
+					t.Errorf("got=%v, want=%v", body, tt.expected.body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/catalog"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/fixtures"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+)
+
+func seedCatalog(ctx context.Context, db *dbtx.DB, args []string) error {
+	size := fixtures.DefaultCatalog
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	seed := fs.Int64("seed", 1, "random `seed`; the same seed loads the same rows")
+	fs.IntVar(&size.Publishers, "publishers", size.Publishers, "number of publishers")
+	fs.IntVar(&size.Authors, "authors", size.Authors, "number of authors")
+	fs.IntVar(&size.Books, "books", size.Books, "number of books")
+	fs.IntVar(&size.AuthorsPerBook, "authors-per-book", size.AuthorsPerBook, "most authors of a book")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if size.Publishers < 0 || size.Authors < 0 || size.Books < 0 {
+		return errors.New("seed: counts must not be negative")
+	}
+
+	var result fixtures.SeedResult
+	err := catalog.New(db).InTx(ctx, func(q *sqlc.Queries) error {
+		var err error
+		result, err = fixtures.Seed(ctx, q, *seed, size)
+		return err
+	})
+	if errors.Is(err, dberr.ErrAlreadyExists) {
+		return fmt.Errorf("seed: the database already holds the catalog of seed %d: %w", *seed, err)
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("seeded %d publishers, %d authors, %d books and %d author-book links",
+		result.Publishers, result.Authors, result.Books, result.AuthorBooks)
+	return nil
+}
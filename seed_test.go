@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dberr"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/fixtures"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/memdb"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/sqlc"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/testdb"
+)
+
+func TestSeedCatalog(t *testing.T) {
+	t.Parallel()
+	db := testdb.New(t)
+
+	args := []string{"-seed", "7", "-publishers", "2", "-authors", "4", "-books", "6"}
+
+	// The same catalog, created in memory.
+	want := memdb.New()
+	size := fixtures.Catalog{Publishers: 2, Authors: 4, Books: 6, AuthorsPerBook: fixtures.DefaultCatalog.AuthorsPerBook}
+	if _, err := fixtures.Seed(context.Background(), want, 7, size); err != nil {
+		t.Fatal(err)
+	}
+	expected, err := want.ListAuthorBooks(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		scenario string
+		input    []string
+		expected error
+	}{
+		{
+			scenario: "seed empty database",
+			input:    args,
+			expected: nil,
+		},
+		{
+			scenario: "seed again",
+			input:    args,
+			expected: dberr.ErrAlreadyExists,
+		},
+	}
+
+	// The scenarios build on each other, so they do not run in parallel.
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.scenario, func(t *testing.T) {
+			ctx := context.Background()
+			err := seedCatalog(ctx, dbtx.Wrap(db), tt.input)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("got=%v, want=%v", err, tt.expected)
+			}
+
+			rows, err := sqlc.New(db).ListAuthorBooks(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, expected) {
+				t.Errorf("got=%v, want=%v", rows, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/database"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/dbtx"
+	"github.com/dot96gal/go-sqlc-mysql-sample/internal/httpapi"
+)
+
+func serve(ctx context.Context, db *dbtx.DB, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := fs.String("addr", ":8080", "HTTP listen address")
+	debugAddr := fs.String("debug-addr", "", "listen address of the unauthenticated debug endpoints, off when empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	servers := []*http.Server{{
+		Addr:              *addr,
+		Handler:           httpapi.New(db),
+		ReadHeaderTimeout: 10 * time.Second,
+	}}
+	if *debugAddr != "" {
+		servers = append(servers, &http.Server{
+			Addr:              *debugAddr,
+			Handler:           httpapi.NewDebug(db),
+			ReadHeaderTimeout: 10 * time.Second,
+		})
+	}
+
+	errCh := make(chan error, len(servers))
+	for _, server := range servers {
+		go func() {
+			log.Printf("listening on %s", server.Addr)
+			errCh <- server.ListenAndServe()
+		}()
+	}
+
+	// a listener that fails takes the others down with it
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, server := range servers {
+		if shutdownErr := server.Shutdown(shutdownCtx); shutdownErr != nil && err == nil {
+			err = shutdownErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	for range servers {
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+	}
+	log.Printf("database pool: %s", database.StatsOf(db.Unwrap()))
+	return nil
+}